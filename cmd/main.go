@@ -1,29 +1,372 @@
 package main
 
 import (
-	"finz/internal"
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/FilippoVissani/finz/internal"
+	"github.com/FilippoVissani/finz/pkg/finance"
 )
 
+const formatUsage = "Output format: table, json or csv"
+
+// format is the output format selected with the global or the command --format flag
+var format = string(internal.FormatTable)
+
+// render writes the result in the selected output format; table writes the human-readable layout
+func render(result any, table func(w io.Writer)) {
+	outputFormat, err := internal.ParseFormat(format)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if err := internal.Render(os.Stdout, outputFormat, result, table); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// prepaymentList collects repeated --prepay flags in the form month:amount[:term|installment]
+type prepaymentList []finance.Prepayment
+
+func (p *prepaymentList) String() string {
+	parts := make([]string, 0, len(*p))
+	for _, prepayment := range *p {
+		parts = append(parts, fmt.Sprintf("%d:%s:%s", prepayment.Month, prepayment.Amount, prepayment.Mode))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (p *prepaymentList) Set(value string) error {
+	fields := strings.Split(value, ":")
+	if len(fields) < 2 || len(fields) > 3 {
+		return fmt.Errorf("invalid prepayment %q, expected month:amount[:term|installment]", value)
+	}
+
+	month, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return fmt.Errorf("invalid prepayment month %q", fields[0])
+	}
+	amount, err := finance.ParseMoney(fields[1])
+	if err != nil {
+		return fmt.Errorf("invalid prepayment amount %q", fields[1])
+	}
+
+	mode := finance.ReduceTerm
+	if len(fields) == 3 {
+		mode = finance.PrepaymentMode(fields[2])
+		if mode != finance.ReduceTerm && mode != finance.ReduceInstallment {
+			return fmt.Errorf("invalid prepayment mode %q, expected term or installment", fields[2])
+		}
+	}
+
+	*p = append(*p, finance.Prepayment{Month: month, Amount: amount, Mode: mode})
+	return nil
+}
+
+// categoryList collects repeated --category flags in the form name=percentage
+type categoryList []finance.BudgetCategoryInput
+
+func (c *categoryList) String() string {
+	parts := make([]string, 0, len(*c))
+	for _, category := range *c {
+		if category.Fixed {
+			parts = append(parts, fmt.Sprintf("%s=€%s", category.Name, category.Amount))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s=%g", category.Name, category.Percentage))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (c *categoryList) Set(value string) error {
+	category, err := finance.ParseBudgetCategory(value)
+	if err != nil {
+		return err
+	}
+	*c = append(*c, category)
+	return nil
+}
+
+// fixedCategoryList adds repeated --fixed flags in the form name=amount to a categoryList,
+// keeping the order of the command line
+type fixedCategoryList struct {
+	categories *categoryList
+}
+
+func (f fixedCategoryList) String() string {
+	if f.categories == nil {
+		return ""
+	}
+	return f.categories.String()
+}
+
+func (f fixedCategoryList) Set(value string) error {
+	category, err := finance.ParseFixedBudgetCategory(value)
+	if err != nil {
+		return err
+	}
+	*f.categories = append(*f.categories, category)
+	return nil
+}
+
+// sinkingFundList collects repeated --sinking flags in the form name=amount:frequency:due-month[:balance]
+type sinkingFundList []finance.SinkingFundInput
+
+func (s *sinkingFundList) String() string {
+	parts := make([]string, 0, len(*s))
+	for _, fund := range *s {
+		parts = append(parts, fmt.Sprintf("%s=%s:%s:%d", fund.Name, fund.Amount, fund.Frequency, fund.DueMonth))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (s *sinkingFundList) Set(value string) error {
+	fund, err := finance.ParseSinkingFund(value)
+	if err != nil {
+		return err
+	}
+	*s = append(*s, fund)
+	return nil
+}
+
+// ruleList collects repeated --rule flags in the form category=match
+type ruleList []finance.CategoryRule
+
+func (r *ruleList) String() string {
+	parts := make([]string, 0, len(*r))
+	for _, rule := range *r {
+		if rule.Payee != nil {
+			parts = append(parts, fmt.Sprintf("%s=/%s/", rule.Category, rule.Payee))
+		} else {
+			parts = append(parts, fmt.Sprintf("%s=%s", rule.Category, rule.Keyword))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (r *ruleList) Set(value string) error {
+	rule, err := finance.ParseCategoryRule(value)
+	if err != nil {
+		return err
+	}
+	*r = append(*r, rule)
+	return nil
+}
+
+// ratePeriodList collects repeated --rate-period flags in the form from-to:rate
+type ratePeriodList []finance.RatePeriod
+
+func (r *ratePeriodList) String() string {
+	parts := make([]string, 0, len(*r))
+	for _, period := range *r {
+		parts = append(parts, fmt.Sprintf("%d-%d:%.2f", period.FromMonth, period.ToMonth, period.Rate))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (r *ratePeriodList) Set(value string) error {
+	months, rateValue, found := strings.Cut(value, ":")
+	if !found {
+		return fmt.Errorf("invalid rate period %q, expected from-to:rate", value)
+	}
+	fromValue, toValue, _ := strings.Cut(months, "-")
+
+	from, err := strconv.Atoi(fromValue)
+	if err != nil {
+		return fmt.Errorf("invalid rate period start %q", fromValue)
+	}
+	to := 0
+	if toValue != "" {
+		if to, err = strconv.Atoi(toValue); err != nil {
+			return fmt.Errorf("invalid rate period end %q", toValue)
+		}
+	}
+	rate, err := strconv.ParseFloat(rateValue, 64)
+	if err != nil {
+		return fmt.Errorf("invalid rate %q", rateValue)
+	}
+
+	*r = append(*r, finance.RatePeriod{FromMonth: from, ToMonth: to, Rate: rate})
+	return nil
+}
+
+// assetList collects repeated --asset flags in the form name=weight:return:volatility
+type assetList []finance.Asset
+
+func (a *assetList) String() string {
+	parts := make([]string, 0, len(*a))
+	for _, asset := range *a {
+		parts = append(parts, fmt.Sprintf("%s=%g:%g:%g", asset.Name, asset.Weight, asset.ExpectedReturn, asset.Volatility))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (a *assetList) Set(value string) error {
+	asset, err := finance.ParseAsset(value)
+	if err != nil {
+		return err
+	}
+	*a = append(*a, asset)
+	return nil
+}
+
+// correlationList collects repeated --correlation flags in the form first,second=value
+type correlationList []finance.AssetCorrelation
+
+func (c *correlationList) String() string {
+	parts := make([]string, 0, len(*c))
+	for _, correlation := range *c {
+		parts = append(parts, fmt.Sprintf("%s,%s=%g", correlation.First, correlation.Second, correlation.Value))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (c *correlationList) Set(value string) error {
+	correlation, err := finance.ParseCorrelation(value)
+	if err != nil {
+		return err
+	}
+	*c = append(*c, correlation)
+	return nil
+}
+
+// simulation holds the Monte Carlo flags shared by invest and retirement
+type simulation struct {
+	runs         int
+	seed         uint64
+	workers      int
+	distribution string
+	volatility   float64
+	returnsFile  string
+	target       finance.Money
+}
+
+func (s *simulation) register(cmd *flag.FlagSet) {
+	cmd.IntVar(&s.runs, "simulate", 0, "Run a Monte Carlo simulation with this number of random return paths")
+	cmd.Uint64Var(&s.seed, "seed", 0, "Seed of the simulation, to reproduce a run (default: random)")
+	cmd.IntVar(&s.workers, "workers", 0, "Goroutines running the simulation (default: the number of CPUs)")
+	cmd.StringVar(&s.distribution, "distribution", string(finance.NormalReturns), "Distribution of the annual returns: normal, lognormal or bootstrap")
+	cmd.Float64Var(&s.volatility, "volatility", 15.0, "Standard deviation of the annual returns in percent")
+	cmd.StringVar(&s.returnsFile, "returns-file", "", "File of historical annual returns in percent for --distribution bootstrap")
+	cmd.TextVar(&s.target, "target", finance.Money{}, "Value whose probability of being reached is reported")
+}
+
+// input builds the simulation input from the parsed flags, exiting on error
+func (s *simulation) input(cmd *flag.FlagSet) finance.SimulationInput {
+	seeded := false
+	cmd.Visit(func(f *flag.Flag) { seeded = seeded || f.Name == "seed" })
+	if !seeded {
+		s.seed = uint64(time.Now().UnixNano())
+	}
+
+	input := finance.SimulationInput{
+		Simulations:  s.runs,
+		Seed:         s.seed,
+		Workers:      s.workers,
+		Distribution: finance.ReturnDistribution(s.distribution),
+		Volatility:   s.volatility,
+		Target:       s.target,
+	}
+	if s.returnsFile != "" {
+		returns, err := finance.LoadReturns(s.returnsFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		input.History = returns
+	}
+	return input
+}
+
+// printSimulation writes the percentiles of a simulation of the named outcome
+func printSimulation(w io.Writer, result finance.SimulationResult, outcome string) {
+	fmt.Fprintf(w, "\nMonte Carlo simulation of the %s\n", outcome)
+	fmt.Fprintf(w, "Simulations:           %d (%s returns, seed %d)\n", result.Simulations, result.Distribution, result.Seed)
+	fmt.Fprintf(w, "Constant yield:        €%s\n", result.ConstantYield)
+	fmt.Fprintf(w, "Mean:                  €%s\n", result.Mean)
+	fmt.Fprintf(w, "P5:                    €%s\n", result.P5)
+	fmt.Fprintf(w, "P25:                   €%s\n", result.P25)
+	fmt.Fprintf(w, "P50 (median):          €%s\n", result.P50)
+	fmt.Fprintf(w, "P75:                   €%s\n", result.P75)
+	fmt.Fprintf(w, "P95:                   €%s\n", result.P95)
+	if result.Target.IsPositive() {
+		fmt.Fprintf(w, "Chance to reach €%s: %.1f%%\n", result.Target, result.TargetProbability)
+	}
+}
+
+// printBacktest writes the outcomes of a backtest and, when kept, of every window
+func printBacktest(w io.Writer, result finance.BacktestResult) {
+	fmt.Fprintf(w, "\nBacktest over every %d-year window of %s returns\n", result.Years, result.Frequency)
+	fmt.Fprintf(w, "Total invested:        €%s\n", result.TotalInvested)
+	fmt.Fprintf(w, "Best:                  €%s (from %s)\n", result.BestValue, result.BestStart)
+	fmt.Fprintf(w, "Median:                €%s (from %s)\n", result.MedianValue, result.MedianStart)
+	fmt.Fprintf(w, "Worst:                 €%s (from %s)\n", result.WorstValue, result.WorstStart)
+	fmt.Fprintf(w, "Worst drawdown:        %.1f%% (from %s)\n", result.WorstDrawdown, result.WorstDrawdownStart)
+
+	if len(result.Windows) > 0 {
+		fmt.Fprintln(w, "\nStart\tEnd\tFinal value\tReal value\tAnnualized\tDrawdown")
+		for _, window := range result.Windows {
+			fmt.Fprintf(w, "%s\t%s\t€%s\t€%s\t%.2f%%\t\t%.1f%%\n",
+				window.Start, window.End, window.FinalValue, window.RealValue, window.AnnualizedReturn, window.MaxDrawdown)
+		}
+	}
+}
+
 func handleInvest(args []string) {
 	investCmd := flag.NewFlagSet("invest", flag.ExitOnError)
 
 	var (
-		principal   float64
-		annualYield float64
-		taxRate     float64
-		inflation   float64
-		years       int
+		principal         finance.Money
+		annualYield       float64
+		taxRate           float64
+		inflation         float64
+		years             int
+		contribution      finance.Money
+		frequency         string
+		increase          float64
+		contributionStart int
+		contributionEnd   int
+		yearly            bool
+		expenseRatio      float64
+		entryFee          float64
+		exitFee           float64
+		custodyFee        finance.Money
+		stampDuty         float64
+		monteCarlo        simulation
+		backtestFile      string
+		windows           bool
 	)
 
-	investCmd.Float64Var(&principal, "initial", 10000, "Initial investment amount")
+	investCmd.TextVar(&principal, "initial", finance.NewMoney(10000), "Initial investment amount")
 	investCmd.Float64Var(&annualYield, "yield", 7.0, "Annual yield in percent (e.g., 7)")
 	investCmd.Float64Var(&taxRate, "tax", 26.0, "Tax rate on gains in percent")
 	investCmd.Float64Var(&inflation, "inflation", 2.0, "Annual inflation rate in percent")
 	investCmd.IntVar(&years, "years", 10, "Investment duration in years")
+	investCmd.TextVar(&contribution, "contribution", finance.Money{}, "Recurring contribution, such as the monthly amount of an accumulation plan")
+	investCmd.StringVar(&frequency, "frequency", string(finance.ContributeMonthly), "Contribution frequency: monthly, quarterly or yearly")
+	investCmd.Float64Var(&increase, "increase", 0, "Yearly increase of the contribution in percent")
+	investCmd.IntVar(&contributionStart, "contribution-start", 1, "First year with contributions")
+	investCmd.IntVar(&contributionEnd, "contribution-end", 0, "Last year with contributions (default: the last year)")
+	investCmd.BoolVar(&yearly, "yearly", false, "Show the year-by-year breakdown")
+	investCmd.Float64Var(&expenseRatio, "ter", 0, "Annual expense ratio (TER) of the fund in percent")
+	investCmd.Float64Var(&entryFee, "entry-fee", 0, "Commission on each amount invested in percent")
+	investCmd.Float64Var(&exitFee, "exit-fee", 0, "Commission on the final value in percent")
+	investCmd.TextVar(&custodyFee, "custody", finance.Money{}, "Fixed yearly custody fee")
+	investCmd.Float64Var(&stampDuty, "stamp-duty", 0, fmt.Sprintf("Yearly stamp duty on the holdings in percent (%g for the Italian imposta di bollo)", finance.ItalianStampDuty))
+	monteCarlo.register(investCmd)
+	investCmd.StringVar(&backtestFile, "backtest", "", "CSV file of historical annual or monthly returns to run the plan over every start year or month")
+	investCmd.BoolVar(&windows, "windows", false, "Show the outcome of every backtest start")
+	investCmd.StringVar(&format, "format", format, formatUsage)
 
 	if err := investCmd.Parse(args); err != nil {
 		fmt.Println(err)
@@ -38,37 +381,142 @@ func handleInvest(args []string) {
 		}
 	}
 
-	input := internal.InvestmentInput{
-		Principal:   principal,
-		AnnualYield: annualYield,
-		TaxRate:     taxRate,
-		Inflation:   inflation,
-		Years:       years,
+	input := finance.InvestmentInput{
+		Principal:             principal,
+		AnnualYield:           annualYield,
+		TaxRate:               taxRate,
+		Inflation:             inflation,
+		Years:                 years,
+		Contribution:          contribution,
+		ContributionFrequency: finance.ContributionFrequency(frequency),
+		ContributionIncrease:  increase,
+		ContributionStart:     contributionStart,
+		ContributionEnd:       contributionEnd,
+		ExpenseRatio:          expenseRatio,
+		EntryFee:              entryFee,
+		ExitFee:               exitFee,
+		CustodyFee:            custodyFee,
+		StampDuty:             stampDuty,
+	}
+
+	result, err := finance.CalculateInvestment(input)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if monteCarlo.runs > 0 && backtestFile != "" {
+		fmt.Println("--simulate and --backtest cannot be combined")
+		os.Exit(1)
+	}
+
+	// A simulation or a backtest replaces the result in the JSON and CSV output
+	var output any = result
+	var simulated finance.SimulationResult
+	if monteCarlo.runs > 0 {
+		if simulated, err = finance.SimulateInvestment(input, monteCarlo.input(investCmd)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		output = simulated
+	}
+
+	var backtest finance.BacktestResult
+	if backtestFile != "" {
+		history, err := finance.LoadReturnHistory(backtestFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if backtest, err = finance.Backtest(finance.BacktestInput{Plan: input, History: history}); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if !windows {
+			backtest.Windows = []finance.BacktestWindow{}
+		}
+		output = backtest
 	}
 
-	result := internal.CalculateInvestment(input)
+	render(output, func(w io.Writer) {
+		fmt.Fprintf(w, "Initial amount:        €%s\n", result.Principal)
+		if result.Contributions.IsPositive() {
+			fmt.Fprintf(w, "Contributions:         €%s\n", result.Contributions)
+			fmt.Fprintf(w, "Total invested:        €%s\n", result.TotalInvested)
+			fmt.Fprintf(w, "Value before tax:      €%s\n", result.FutureValue)
+			fmt.Fprintf(w, "Gains:                 €%s\n", result.Gains)
+		}
+		fmt.Fprintf(w, "Nominal final value:   €%s\n", result.NetFutureValue)
+		fmt.Fprintf(w, "Real final value:      €%s\n", result.RealValue)
+		fmt.Fprintf(w, "Total tax paid:        €%s\n", result.TaxPaid)
+		if result.FeesPaid.IsPositive() {
+			fmt.Fprintf(w, "Total fees paid:       €%s\n", result.FeesPaid)
+			fmt.Fprintf(w, "Fee drag:              €%s (%.2f%% of the value without costs)\n", result.FeeDrag, result.FeeDragPercentage)
+		}
+		fmt.Fprintf(w, "Total years:           %d\n", result.Years)
+
+		if yearly && len(result.YearlyDetails) > 0 {
+			fmt.Fprintln(w, "\nYearly Breakdown:")
+			fmt.Fprintln(w, "Year\tContributions\tInvested\tGrowth\t\tFees\t\tValue\t\tTax\t\tNet value\tReal value")
 
-	fmt.Printf("Initial amount:        €%.2f\n", result.Principal)
-	fmt.Printf("Nominal final value:   €%.2f\n", result.NetFutureValue)
-	fmt.Printf("Real final value:      €%.2f\n", result.RealValue)
-	fmt.Printf("Total tax paid:        €%.2f\n", result.TaxPaid)
-	fmt.Printf("Total years:           %d\n", result.Years)
+			for _, detail := range result.YearlyDetails {
+				fmt.Fprintf(w, "%d\t€%s\t€%s\t€%s\t€%s\t€%s\t€%s\t€%s\t€%s\n",
+					detail.Year, detail.Contributions, detail.Invested, detail.Growth, detail.Fees, detail.Value, detail.Tax, detail.NetValue, detail.RealValue)
+			}
+		}
+
+		if monteCarlo.runs > 0 {
+			printSimulation(w, simulated, "nominal final value")
+		}
+		if backtestFile != "" {
+			printBacktest(w, backtest)
+		}
+	})
 }
 
 func handleLoan(args []string) {
 	loanCmd := flag.NewFlagSet("loan", flag.ExitOnError)
 
 	var (
-		principal float64
-		rate      float64
-		years     int
-		monthly   bool
+		principal   finance.Money
+		rate        float64
+		years       int
+		monthly     bool
+		yearly      bool
+		fromMonth   int
+		toMonth     int
+		extra       finance.Money
+		prepayments prepaymentList
+		method      string
+		ratePath    ratePeriodList
+		indexFile   string
+		spread      float64
+		rateCap     float64
+		rateFloor   float64
+		costs       finance.LoanCosts
 	)
 
-	loanCmd.Float64Var(&principal, "amount", 100000, "Loan amount")
+	loanCmd.TextVar(&principal, "amount", finance.NewMoney(100000), "Loan amount")
 	loanCmd.Float64Var(&rate, "rate", 4.5, "Annual interest rate in percent")
 	loanCmd.IntVar(&years, "years", 30, "Loan term in years")
+	loanCmd.StringVar(&method, "method", "french", "Amortization method: french, italian, interest-only or bullet")
 	loanCmd.BoolVar(&monthly, "monthly", true, "Show monthly payment breakdown")
+	loanCmd.BoolVar(&yearly, "yearly", false, "Show yearly payment breakdown instead of monthly")
+	loanCmd.IntVar(&fromMonth, "from", 0, "First month of the breakdown (default: first payment)")
+	loanCmd.IntVar(&toMonth, "to", 0, "Last month of the breakdown (default: last payment)")
+	loanCmd.TextVar(&extra, "extra", finance.Money{}, "Extra principal paid every month")
+	loanCmd.Var(&prepayments, "prepay", "One-off prepayment as month:amount[:term|installment] (repeatable)")
+	loanCmd.Var(&ratePath, "rate-period", "Rate for a range of months as from-to:rate, 'to' may be omitted (repeatable)")
+	loanCmd.StringVar(&indexFile, "index-file", "", "CSV file of month,rate base-index values for a variable rate")
+	loanCmd.Float64Var(&spread, "spread", 0, "Spread in percent added to the index")
+	loanCmd.Float64Var(&rateCap, "cap", 0, "Maximum variable rate in percent (0 = no cap)")
+	loanCmd.Float64Var(&rateFloor, "floor", 0, "Minimum variable rate in percent")
+	loanCmd.TextVar(&costs.OriginationFee, "origination-fee", finance.Money{}, "Upfront origination fee")
+	loanCmd.TextVar(&costs.AppraisalFee, "appraisal-fee", finance.Money{}, "Upfront appraisal fee")
+	loanCmd.TextVar(&costs.NotaryFee, "notary-fee", finance.Money{}, "Upfront notary fee")
+	loanCmd.TextVar(&costs.MonthlyFee, "monthly-fee", finance.Money{}, "Fee charged with each installment")
+	loanCmd.TextVar(&costs.MonthlyInsurance, "insurance", finance.Money{}, "Mandatory insurance premium charged with each installment")
+	loanCmd.StringVar(&format, "format", format, formatUsage)
 
 	if err := loanCmd.Parse(args); err != nil {
 		fmt.Println(err)
@@ -83,49 +531,101 @@ func handleLoan(args []string) {
 		}
 	}
 
-	input := internal.LoanInput{
-		Principal: principal,
-		Rate:      rate,
-		Years:     years,
-		Monthly:   monthly,
+	var indexSeries []finance.RatePeriod
+	if indexFile != "" {
+		series, err := finance.LoadRateSeries(indexFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		indexSeries = series
+	}
+
+	input := finance.LoanInput{
+		Principal:    principal,
+		Rate:         rate,
+		Years:        years,
+		Method:       finance.AmortizationMethod(method),
+		Monthly:      monthly && !yearly,
+		Yearly:       yearly,
+		FromMonth:    fromMonth,
+		ToMonth:      toMonth,
+		ExtraPayment: extra,
+		Prepayments:  prepayments,
+		RatePath:     ratePath,
+		IndexSeries:  indexSeries,
+		Spread:       spread,
+		RateCap:      rateCap,
+		RateFloor:    rateFloor,
+		Costs:        costs,
+	}
+
+	result, err := finance.CalculateLoan(input)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
 
-	result := internal.CalculateLoan(input)
+	render(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Loan amount:           €%s\n", result.Principal)
+		fmt.Fprintf(w, "Amortization method:   %s\n", result.Method)
+		fmt.Fprintf(w, "Monthly payment:       €%s\n", result.MonthlyPayment)
+		if result.Method != finance.French {
+			fmt.Fprintf(w, "Final payment:         €%s\n", result.FinalPayment)
+		}
+		fmt.Fprintf(w, "Total paid:            €%s\n", result.TotalPaid)
+		fmt.Fprintf(w, "Total interest:        €%s\n", result.TotalInterest)
+		if result.TotalFees.IsPositive() {
+			fmt.Fprintf(w, "Total fees:            €%s\n", result.TotalFees)
+		}
+		fmt.Fprintf(w, "APR (TAEG):            %.2f%%\n", result.APR)
+		fmt.Fprintf(w, "Loan term:             %d years (%d payments)\n", result.Years, result.NumberOfPayments)
 
-	fmt.Printf("Loan amount:           €%.2f\n", result.Principal)
-	fmt.Printf("Monthly payment:       €%.2f\n", result.MonthlyPayment)
-	fmt.Printf("Total paid:            €%.2f\n", result.TotalPaid)
-	fmt.Printf("Total interest:        €%.2f\n", result.TotalInterest)
-	fmt.Printf("Loan term:             %d years (%d payments)\n", result.Years, result.NumberOfPayments)
+		if extra.IsPositive() || len(prepayments) > 0 {
+			fmt.Fprintf(w, "Payoff month:          %d\n", result.PayoffMonth)
+			fmt.Fprintf(w, "Months saved:          %d\n", result.MonthsSaved)
+			fmt.Fprintf(w, "Interest saved:        €%s\n", result.InterestSaved)
+		}
 
-	if monthly && len(result.MonthlyDetails) > 0 {
-		fmt.Println("\nMonthly Payment Breakdown:")
-		fmt.Println("Month\tPayment\t\tPrincipal\tInterest\tRemaining")
+		if len(result.MonthlyDetails) > 0 {
+			fmt.Fprintln(w, "\nMonthly Payment Breakdown:")
+			fmt.Fprintln(w, "Month\tRate\tPayment\t\tPrincipal\tInterest\tExtra\t\tRemaining")
 
-		for _, detail := range result.MonthlyDetails {
-			fmt.Printf("%d\t€%.2f\t\t€%.2f\t\t€%.2f\t\t€%.2f\n",
-				detail.Month, detail.Payment, detail.PrincipalPayment, detail.InterestPayment, detail.RemainingBalance)
+			for _, detail := range result.MonthlyDetails {
+				fmt.Fprintf(w, "%d\t%.2f%%\t€%s\t\t€%s\t\t€%s\t\t€%s\t\t€%s\n",
+					detail.Month, detail.Rate, detail.Payment, detail.PrincipalPayment, detail.InterestPayment, detail.ExtraPayment, detail.RemainingBalance)
+			}
 		}
-		fmt.Println("...")
-	}
+
+		if len(result.YearlyDetails) > 0 {
+			fmt.Fprintln(w, "\nYearly Payment Breakdown:")
+			fmt.Fprintln(w, "Year\tPayment\t\tPrincipal\tInterest\tExtra\t\tRemaining")
+
+			for _, detail := range result.YearlyDetails {
+				fmt.Fprintf(w, "%d\t€%s\t€%s\t€%s\t€%s\t€%s\n",
+					detail.Year, detail.Payment, detail.PrincipalPayment, detail.InterestPayment, detail.ExtraPayment, detail.RemainingBalance)
+			}
+		}
+	})
 }
 
 func handleSavings(args []string) {
 	savingsCmd := flag.NewFlagSet("savings", flag.ExitOnError)
 
 	var (
-		initial        float64
-		monthlyDeposit float64
+		initial        finance.Money
+		monthlyDeposit finance.Money
 		annualYield    float64
 		inflation      float64
 		years          int
 	)
 
-	savingsCmd.Float64Var(&initial, "initial", 1000, "Initial deposit amount")
-	savingsCmd.Float64Var(&monthlyDeposit, "monthly", 100, "Monthly deposit amount")
+	savingsCmd.TextVar(&initial, "initial", finance.NewMoney(1000), "Initial deposit amount")
+	savingsCmd.TextVar(&monthlyDeposit, "monthly", finance.NewMoney(100), "Monthly deposit amount")
 	savingsCmd.Float64Var(&annualYield, "yield", 3.0, "Annual yield in percent")
 	savingsCmd.Float64Var(&inflation, "inflation", 2.0, "Annual inflation rate in percent")
 	savingsCmd.IntVar(&years, "years", 10, "Savings duration in years")
+	savingsCmd.StringVar(&format, "format", format, formatUsage)
 
 	if err := savingsCmd.Parse(args); err != nil {
 		fmt.Println(err)
@@ -140,7 +640,7 @@ func handleSavings(args []string) {
 		}
 	}
 
-	input := internal.SavingsInput{
+	input := finance.SavingsInput{
 		Initial:        initial,
 		MonthlyDeposit: monthlyDeposit,
 		AnnualYield:    annualYield,
@@ -148,15 +648,21 @@ func handleSavings(args []string) {
 		Years:          years,
 	}
 
-	result := internal.CalculateSavings(input)
+	result, err := finance.CalculateSavings(input)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
 
-	fmt.Printf("Initial deposit:       €%.2f\n", result.Initial)
-	fmt.Printf("Monthly deposit:       €%.2f\n", result.MonthlyDeposit)
-	fmt.Printf("Nominal final balance: €%.2f\n", result.FutureValue)
-	fmt.Printf("Real final balance:    €%.2f\n", result.RealFutureValue)
-	fmt.Printf("Total deposits:        €%.2f\n", result.TotalDeposits)
-	fmt.Printf("Interest earned:       €%.2f\n", result.InterestEarned)
-	fmt.Printf("Savings period:        %d years (%d months)\n", result.Years, result.NumberOfMonths)
+	render(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Initial deposit:       €%s\n", result.Initial)
+		fmt.Fprintf(w, "Monthly deposit:       €%s\n", result.MonthlyDeposit)
+		fmt.Fprintf(w, "Nominal final balance: €%s\n", result.FutureValue)
+		fmt.Fprintf(w, "Real final balance:    €%s\n", result.RealFutureValue)
+		fmt.Fprintf(w, "Total deposits:        €%s\n", result.TotalDeposits)
+		fmt.Fprintf(w, "Interest earned:       €%s\n", result.InterestEarned)
+		fmt.Fprintf(w, "Savings period:        %d years (%d months)\n", result.Years, result.NumberOfMonths)
+	})
 }
 
 func handleRetirement(args []string) {
@@ -165,20 +671,23 @@ func handleRetirement(args []string) {
 	var (
 		currentAge          int
 		retirementAge       int
-		currentSavings      float64
-		monthlyContribution float64
+		currentSavings      finance.Money
+		monthlyContribution finance.Money
 		withdrawalRate      float64
 		annualYield         float64
 		inflation           float64
+		monteCarlo          simulation
 	)
 
 	retireCmd.IntVar(&currentAge, "age", 30, "Current age")
 	retireCmd.IntVar(&retirementAge, "retire-age", 65, "Retirement age")
-	retireCmd.Float64Var(&currentSavings, "savings", 50000, "Current retirement savings")
-	retireCmd.Float64Var(&monthlyContribution, "monthly", 500, "Monthly contribution")
+	retireCmd.TextVar(&currentSavings, "savings", finance.NewMoney(50000), "Current retirement savings")
+	retireCmd.TextVar(&monthlyContribution, "monthly", finance.NewMoney(500), "Monthly contribution")
 	retireCmd.Float64Var(&withdrawalRate, "withdrawal", 4.0, "Annual withdrawal rate in percent")
 	retireCmd.Float64Var(&annualYield, "yield", 7.0, "Annual investment yield in percent")
 	retireCmd.Float64Var(&inflation, "inflation", 2.0, "Annual inflation rate in percent")
+	monteCarlo.register(retireCmd)
+	retireCmd.StringVar(&format, "format", format, formatUsage)
 
 	if err := retireCmd.Parse(args); err != nil {
 		fmt.Println(err)
@@ -193,7 +702,7 @@ func handleRetirement(args []string) {
 		}
 	}
 
-	input := internal.RetirementInput{
+	input := finance.RetirementInput{
 		CurrentAge:          currentAge,
 		RetirementAge:       retirementAge,
 		CurrentSavings:      currentSavings,
@@ -203,29 +712,230 @@ func handleRetirement(args []string) {
 		Inflation:           inflation,
 	}
 
-	result := internal.CalculateRetirement(input)
+	result, err := finance.CalculateRetirement(input)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	// A simulation replaces the result in the JSON and CSV output
+	var output any = result
+	var simulated finance.SimulationResult
+	if monteCarlo.runs > 0 {
+		if simulated, err = finance.SimulateRetirement(input, monteCarlo.input(retireCmd)); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		output = simulated
+	}
+
+	render(output, func(w io.Writer) {
+		fmt.Fprintf(w, "Current age:           %d\n", result.CurrentAge)
+		fmt.Fprintf(w, "Retirement age:        %d\n", result.RetirementAge)
+		fmt.Fprintf(w, "Years to retirement:   %d\n", result.YearsToRetirement)
+		fmt.Fprintf(w, "Retirement savings:    €%s\n", result.RetirementSavings)
+		fmt.Fprintf(w, "Annual withdrawal:     €%s\n", result.AnnualWithdrawal)
+		fmt.Fprintf(w, "Monthly withdrawal:    €%s\n", result.MonthlyWithdrawal)
+		fmt.Fprintf(w, "Inflation-adjusted monthly withdrawal: €%s\n", result.RealMonthlyWithdrawal)
+
+		if monteCarlo.runs > 0 {
+			printSimulation(w, simulated, "retirement savings")
+		}
+	})
+}
+
+func handlePortfolio(args []string) {
+	portfolioCmd := flag.NewFlagSet("portfolio", flag.ExitOnError)
+
+	var (
+		principal    finance.Money
+		years        int
+		assets       assetList
+		correlations correlationList
+		policy       string
+		every        int
+		band         float64
+		taxRate      float64
+		seed         uint64
+		trades       bool
+	)
+
+	portfolioCmd.TextVar(&principal, "initial", finance.NewMoney(10000), "Initial investment amount")
+	portfolioCmd.IntVar(&years, "years", 10, "Investment duration in years")
+	portfolioCmd.Var(&assets, "asset", "Asset as name=weight:return:volatility in percent (repeatable, default: Stocks=60:7:15 and Bonds=40:3:5)")
+	portfolioCmd.Var(&correlations, "correlation", "Correlation of two assets as first,second=value (repeatable, default: uncorrelated)")
+	portfolioCmd.StringVar(&policy, "rebalance", string(finance.CalendarRebalance), "Rebalancing policy: never, calendar or threshold")
+	portfolioCmd.IntVar(&every, "every", 12, "Months between calendar rebalancings")
+	portfolioCmd.Float64Var(&band, "band", finance.DefaultRebalanceBand, "Drift in percentage points that triggers a threshold rebalancing, above 0")
+	portfolioCmd.Float64Var(&taxRate, "tax", 26.0, "Tax rate in percent on the gains realized by rebalancing")
+	portfolioCmd.Uint64Var(&seed, "seed", 0, "Seed of the random returns, to reproduce a run (default: random)")
+	portfolioCmd.BoolVar(&trades, "trades", false, "List every rebalancing trade")
+	portfolioCmd.StringVar(&format, "format", format, formatUsage)
+
+	if err := portfolioCmd.Parse(args); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if portfolioCmd.Parsed() {
+		if portfolioCmd.NArg() > 0 {
+			fmt.Printf("Unknown arguments: %s\n", strings.Join(portfolioCmd.Args(), " "))
+			portfolioCmd.PrintDefaults()
+			os.Exit(1)
+		}
+	}
+
+	if len(assets) == 0 {
+		assets = assetList{
+			{Name: "Stocks", Weight: 60, ExpectedReturn: 7, Volatility: 15},
+			{Name: "Bonds", Weight: 40, ExpectedReturn: 3, Volatility: 5},
+		}
+	}
+	seeded := false
+	portfolioCmd.Visit(func(f *flag.Flag) { seeded = seeded || f.Name == "seed" })
+	if !seeded {
+		seed = uint64(time.Now().UnixNano())
+	}
+
+	input := finance.PortfolioInput{
+		Principal:       principal,
+		Assets:          assets,
+		Years:           years,
+		Policy:          finance.RebalancePolicy(policy),
+		RebalanceMonths: every,
+		Band:            band,
+		TaxRate:         taxRate,
+		Seed:            seed,
+	}
+	if len(correlations) > 0 {
+		matrix, err := finance.CorrelationMatrix(assets, correlations)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		input.Correlations = matrix
+	}
+
+	result, err := finance.ProjectPortfolio(input)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if !trades {
+		result.Trades = []finance.RebalanceTrade{}
+	}
+
+	render(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Initial investment:    €%s\n", result.Principal)
+		fmt.Fprintf(w, "Final value:           €%s\n", result.FinalValue)
+		fmt.Fprintf(w, "Duration:              %d years (seed %d)\n", result.Years, result.Seed)
+		fmt.Fprintf(w, "Rebalancing:           %s, %d times\n", result.Policy, result.Rebalances)
+		fmt.Fprintf(w, "Turnover:              €%s\n", result.Turnover)
+		fmt.Fprintf(w, "Tax on rebalancing:    €%s\n", result.TaxPaid)
+
+		fmt.Fprintln(w, "\nAsset\tTarget\tWeight\tValue\tCost basis")
+		for _, asset := range result.Assets {
+			fmt.Fprintf(w, "%s\t%.1f%%\t%.1f%%\t€%s\t€%s\n", asset.Name, asset.Target, asset.Weight, asset.Value, asset.Basis)
+		}
+
+		fmt.Fprintln(w, "\nYear\tAsset\tValue\tWeight\tDrift")
+		for _, drift := range result.Drift {
+			fmt.Fprintf(w, "%d\t%s\t€%s\t%.1f%%\t%+.1f\n", drift.Year, drift.Asset, drift.Value, drift.Weight, drift.Drift)
+		}
+
+		if len(result.Trades) > 0 {
+			fmt.Fprintln(w, "\nMonth\tAsset\tAmount\tGain\tTax")
+			for _, trade := range result.Trades {
+				fmt.Fprintf(w, "%d\t%s\t€%s\t€%s\t€%s\n", trade.Month, trade.Asset, trade.Amount, trade.Gain, trade.Tax)
+			}
+		}
+	})
+}
 
-	fmt.Printf("Current age:           %d\n", result.CurrentAge)
-	fmt.Printf("Retirement age:        %d\n", result.RetirementAge)
-	fmt.Printf("Years to retirement:   %d\n", result.YearsToRetirement)
-	fmt.Printf("Retirement savings:    €%.2f\n", result.RetirementSavings)
-	fmt.Printf("Annual withdrawal:     €%.2f\n", result.AnnualWithdrawal)
-	fmt.Printf("Monthly withdrawal:    €%.2f\n", result.MonthlyWithdrawal)
-	fmt.Printf("Inflation-adjusted monthly withdrawal: €%.2f\n", result.RealMonthlyWithdrawal)
+// rateSource holds the currency flags that select where exchange rates come from
+type rateSource struct {
+	ratesFile   string
+	historyFile string
+	fetch       bool
+	ratesURL    string
+	cacheDir    string
+	maxAge      time.Duration
+	stale       string
+}
+
+func (r *rateSource) register(cmd *flag.FlagSet) {
+	cmd.StringVar(&r.ratesFile, "rates-file", "", "Exchange rates file: .json, .csv or ECB eurofxref .xml (default: built-in rates)")
+	cmd.StringVar(&r.historyFile, "history-file", "", "ECB historical rates file: eurofxref-hist .csv, .xml or .zip")
+	cmd.BoolVar(&r.fetch, "fetch", false, "Fetch current rates over HTTP, cached for offline use")
+	cmd.StringVar(&r.ratesURL, "rates-url", finance.DefaultRatesURL, "Rates endpoint for --fetch: ECB eurofxref XML or JSON")
+	cmd.StringVar(&r.cacheDir, "cache-dir", "", "Directory of the rates cache (default: finz in the user config directory)")
+	cmd.DurationVar(&r.maxAge, "max-age", finance.DefaultMaxAge, "Age after which cached rates are stale")
+	cmd.StringVar(&r.stale, "stale", string(finance.StaleRefresh), "Stale rates policy: refresh, warn or refuse")
+}
+
+// provider loads the selected exchange rates, exiting on error
+func (r *rateSource) provider() finance.RateProvider {
+	switch {
+	case r.historyFile != "":
+		history, err := finance.LoadRateHistory(r.historyFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return history
+	case r.ratesFile != "":
+		table, err := finance.LoadRatesFile(r.ratesFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		return table
+	case r.fetch:
+		fetcher := &finance.RateFetcher{
+			URL:      r.ratesURL,
+			CacheDir: r.cacheDir,
+			MaxAge:   r.maxAge,
+			Policy:   finance.StalePolicy(r.stale),
+		}
+		rates, err := fetcher.Rates(context.Background())
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if rates.Warning != "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", rates.Warning)
+		}
+		return rates
+	default:
+		return finance.StaticRates()
+	}
 }
 
 func handleCurrency(args []string) {
+	if len(args) > 0 && args[0] == "convert-file" {
+		handleCurrencyConvertFile(args[1:])
+		return
+	}
+
 	currencyCmd := flag.NewFlagSet("currency", flag.ExitOnError)
 
 	var (
-		amount float64
-		from   string
-		to     string
+		amount  finance.Money
+		from    string
+		to      string
+		date    string
+		base    string
+		sources rateSource
 	)
 
-	currencyCmd.Float64Var(&amount, "amount", 100, "Amount to convert")
+	currencyCmd.TextVar(&amount, "amount", finance.NewMoney(100), "Amount to convert")
 	currencyCmd.StringVar(&from, "from", "EUR", "Source currency code (e.g., EUR, USD)")
 	currencyCmd.StringVar(&to, "to", "USD", "Target currency code (e.g., EUR, USD)")
+	currencyCmd.StringVar(&base, "base", finance.DefaultBaseCurrency, "Currency used to derive cross rates when no direct rate exists")
+	currencyCmd.StringVar(&date, "date", "", "Use the rate of this day (YYYY-MM-DD), requires --history-file")
+	sources.register(currencyCmd)
+	currencyCmd.StringVar(&format, "format", format, formatUsage)
 
 	if err := currencyCmd.Parse(args); err != nil {
 		fmt.Println(err)
@@ -240,47 +950,244 @@ func handleCurrency(args []string) {
 		}
 	}
 
-	input := internal.CurrencyInput{
+	input := finance.CurrencyInput{
 		Amount: amount,
 		From:   from,
 		To:     to,
+		Base:   base,
 	}
 
-	result := internal.ConvertCurrency(input)
+	if date != "" {
+		if sources.historyFile == "" {
+			fmt.Println("--date requires --history-file")
+			os.Exit(1)
+		}
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			fmt.Printf("invalid date %q, expected YYYY-MM-DD\n", date)
+			os.Exit(1)
+		}
+		input.Date = day
+	}
 
-	if result.Error != nil {
-		fmt.Println(result.Error)
+	result, err := finance.ConvertCurrency(input, sources.provider())
+	if err != nil {
+		fmt.Println(err)
 		os.Exit(1)
 	}
 
-	fmt.Printf("%.2f %s = %.2f %s\n", result.Amount, result.From, result.Converted, result.To)
-	fmt.Printf("Exchange rate: 1 %s = %.4f %s\n", result.From, result.ExchangeRate, result.To)
+	render(result, func(w io.Writer) {
+		source, _ := finance.LookupCurrency(result.From)
+		target, _ := finance.LookupCurrency(result.To)
+
+		fmt.Fprintf(w, "%s %s = %s %s\n", result.Amount.Format(source.MinorUnits), result.From, result.Converted.Format(target.MinorUnits), result.To)
+		fmt.Fprintf(w, "Exchange rate: 1 %s = %.4f %s\n", result.From, result.ExchangeRate, result.To)
+		if len(result.Path) > 2 {
+			fmt.Fprintf(w, "Cross rate via: %s\n", strings.Join(result.Path, " → "))
+		}
+		if !result.AsOf.IsZero() {
+			fmt.Fprintf(w, "Rates as of:   %s\n", result.AsOf.Format("2006-01-02"))
+		}
+		if result.Note != "" {
+			fmt.Fprintf(w, "Note: %s\n", result.Note)
+		}
+	})
 }
 
-func handleBudget(args []string) {
-	budgetCmd := flag.NewFlagSet("budget", flag.ExitOnError)
+func handleCurrencyConvertFile(args []string) {
+	convertCmd := flag.NewFlagSet("currency convert-file", flag.ExitOnError)
 
 	var (
-		income        float64
-		housing       float64
-		food          float64
-		transport     float64
-		utilities     float64
-		healthcare    float64
-		debt          float64
-		savings       float64
-		discretionary float64
+		inputPath  string
+		outputPath string
+		to         string
+		base       string
+		columns    finance.CurrencyFileInput
+		sources    rateSource
 	)
 
-	budgetCmd.Float64Var(&income, "income", 3000, "Monthly income")
-	budgetCmd.Float64Var(&housing, "housing", 30, "Housing percentage")
-	budgetCmd.Float64Var(&food, "food", 15, "Food percentage")
-	budgetCmd.Float64Var(&transport, "transport", 10, "Transportation percentage")
-	budgetCmd.Float64Var(&utilities, "utilities", 5, "Utilities percentage")
-	budgetCmd.Float64Var(&healthcare, "healthcare", 5, "Healthcare percentage")
-	budgetCmd.Float64Var(&debt, "debt", 10, "Debt repayment percentage")
-	budgetCmd.Float64Var(&savings, "savings", 15, "Savings percentage")
-	budgetCmd.Float64Var(&discretionary, "discretionary", 10, "Discretionary spending percentage")
+	convertCmd.StringVar(&inputPath, "input", "", "CSV file with amount and currency columns, and optionally a date column")
+	convertCmd.StringVar(&outputPath, "output", "", "Converted CSV file (default: the input name with the target currency, e.g. expenses.EUR.csv)")
+	convertCmd.StringVar(&to, "to", "EUR", "Target currency code")
+	convertCmd.StringVar(&base, "base", finance.DefaultBaseCurrency, "Currency used to derive cross rates when no direct rate exists")
+	convertCmd.StringVar(&columns.AmountColumn, "amount-column", "amount", "Header of the amount column")
+	convertCmd.StringVar(&columns.CurrencyColumn, "currency-column", "currency", "Header of the currency column")
+	convertCmd.StringVar(&columns.DateColumn, "date-column", "", "Header of the date column (default: date, if present)")
+	sources.register(convertCmd)
+	convertCmd.StringVar(&format, "format", format, formatUsage)
+
+	if err := convertCmd.Parse(args); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if convertCmd.Parsed() {
+		if convertCmd.NArg() > 0 {
+			fmt.Printf("Unknown arguments: %s\n", strings.Join(convertCmd.Args(), " "))
+			convertCmd.PrintDefaults()
+			os.Exit(1)
+		}
+	}
+
+	if inputPath == "" {
+		fmt.Println("--input is required")
+		convertCmd.PrintDefaults()
+		os.Exit(1)
+	}
+	if outputPath == "" {
+		extension := filepath.Ext(inputPath)
+		outputPath = strings.TrimSuffix(inputPath, extension) + "." + strings.ToUpper(to) + ".csv"
+	}
+
+	inputAbs, err := filepath.Abs(inputPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	outputAbs, err := filepath.Abs(outputPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if inputAbs == outputAbs {
+		fmt.Println("--output must differ from --input")
+		os.Exit(1)
+	}
+
+	input, err := os.Open(inputAbs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	defer input.Close()
+
+	// Resolve the rates before touching the output, and write to a temporary
+	// file that replaces the output only once the whole file is converted
+	provider := sources.provider()
+	output, err := os.CreateTemp(filepath.Dir(outputAbs), "."+filepath.Base(outputAbs)+".*")
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	columns.To = to
+	columns.Base = base
+	result, err := finance.ConvertCurrencyCSV(input, output, columns, provider)
+	if err == nil {
+		err = output.Chmod(0o644) // CreateTemp makes the file private
+	}
+	if closeErr := output.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(output.Name(), outputAbs)
+	}
+	if err != nil {
+		_ = os.Remove(output.Name())
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if result.Warning != "" {
+		fmt.Fprintf(os.Stderr, "Warning: %s\n", result.Warning)
+	}
+
+	render(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Converted %d rows to %s, written to %s\n\n", result.Rows, result.To, outputPath)
+		fmt.Fprintln(w, "Currency\tRows\tAmount\t\tConverted")
+
+		for _, total := range result.Totals {
+			fmt.Fprintf(w, "%s\t\t%d\t%s\t\t%s %s\n", total.Currency, total.Rows, total.Amount, total.Converted, result.To)
+		}
+
+		fmt.Fprintf(w, "\nTotal:  %s %s\n", result.Total, result.To)
+	})
+}
+
+// budgetPlan holds the budget flags that describe the plan
+type budgetPlan struct {
+	income         finance.Money
+	categories     categoryList
+	categoriesFile string
+	strategy       string
+	savingsRate    float64
+	percentagesOf  string
+	sinking        sinkingFundList
+	sinkingStart   string
+	defaults       []finance.BudgetCategoryInput
+}
+
+// defaultCategoryFlags are the flags of the default template categories, in order
+var defaultCategoryFlags = []string{"housing", "food", "transport", "utilities", "healthcare", "debt", "savings", "discretionary"}
+
+func (p *budgetPlan) register(cmd *flag.FlagSet) {
+	cmd.TextVar(&p.income, "income", finance.NewMoney(3000), "Monthly income")
+	cmd.Var(&p.categories, "category", "Budget category as name=percentage[:group] or name=€amount[:group] (repeatable, replaces the default template)")
+	cmd.Var(fixedCategoryList{&p.categories}, "fixed", "Budget category with a fixed amount as name=amount[:group] (repeatable)")
+	cmd.StringVar(&p.categoriesFile, "categories-file", "", "CSV file of name,share[,group] budget categories")
+	cmd.StringVar(&p.strategy, "template", string(finance.PercentageBudget), "Budget strategy: percentage, 50-30-20, zero-based or pay-yourself-first")
+	cmd.Float64Var(&p.savingsRate, "savings-rate", finance.DefaultSavingsRate, "Share of income saved first by the pay-yourself-first strategy, in percent")
+	cmd.StringVar(&p.percentagesOf, "percentages-of", string(finance.PercentOfRemainder), "What percentages apply to: remainder (after fixed amounts) or income")
+	cmd.Var(&p.sinking, "sinking", "Sinking fund for an irregular expense as name=amount:frequency:due-month[:balance], frequency annual, semiannual or quarterly (repeatable)")
+	cmd.StringVar(&p.sinkingStart, "sinking-start", time.Now().Format("2006-01"), "First month of the sinking fund projection (YYYY-MM)")
+
+	// The percentages of the default template keep their own flags
+	p.defaults = finance.DefaultBudgetCategories()
+	for i := range p.defaults {
+		cmd.Float64Var(&p.defaults[i].Percentage, defaultCategoryFlags[i], p.defaults[i].Percentage, p.defaults[i].Name+" percentage of the default template")
+	}
+}
+
+// input builds the budget input from the parsed flags, exiting on error
+func (p *budgetPlan) input(cmd *flag.FlagSet) finance.BudgetInput {
+	selected := []finance.BudgetCategoryInput(p.categories)
+	if p.categoriesFile != "" {
+		loaded, err := finance.LoadBudgetCategories(p.categoriesFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		selected = append(loaded, selected...)
+	}
+
+	if len(selected) == 0 {
+		selected = p.defaults
+	} else {
+		cmd.Visit(func(f *flag.Flag) {
+			if slices.Contains(defaultCategoryFlags, f.Name) {
+				fmt.Printf("--%s only applies to the default template, use --category %s=%s instead\n", f.Name, f.Name, f.Value)
+				os.Exit(1)
+			}
+		})
+	}
+
+	month, err := time.Parse("2006-01", p.sinkingStart)
+	if err != nil {
+		fmt.Printf("Invalid sinking fund start %q, expected YYYY-MM\n", p.sinkingStart)
+		os.Exit(1)
+	}
+
+	return finance.BudgetInput{
+		Income:         p.income,
+		Categories:     selected,
+		Strategy:       finance.BudgetStrategy(p.strategy),
+		SavingsRate:    p.savingsRate,
+		PercentageBase: finance.PercentageBase(p.percentagesOf),
+		SinkingFunds:   p.sinking,
+		Month:          month,
+	}
+}
+
+func handleBudget(args []string) {
+	if len(args) > 0 && args[0] == "actual" {
+		handleBudgetActual(args[1:])
+		return
+	}
+
+	budgetCmd := flag.NewFlagSet("budget", flag.ExitOnError)
+
+	var plan budgetPlan
+	plan.register(budgetCmd)
+	budgetCmd.StringVar(&format, "format", format, formatUsage)
 
 	if err := budgetCmd.Parse(args); err != nil {
 		fmt.Println(err)
@@ -295,32 +1202,223 @@ func handleBudget(args []string) {
 		}
 	}
 
-	input := internal.BudgetInput{
-		Income:        income,
-		Housing:       housing,
-		Food:          food,
-		Transport:     transport,
-		Utilities:     utilities,
-		Healthcare:    healthcare,
-		Debt:          debt,
-		Savings:       savings,
-		Discretionary: discretionary,
+	input := plan.input(budgetCmd)
+
+	result, err := finance.AllocateBudget(input)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
 
-	result := internal.AllocateBudget(input)
+	render(result, func(w io.Writer) {
+		if result.Warning != "" {
+			fmt.Fprintf(w, "%s\n\n", result.Warning)
+		}
 
-	if result.Warning != "" {
-		fmt.Printf("%s\n\n", result.Warning)
+		fmt.Fprintf(w, "Monthly Income: €%s\n", result.Income)
+		fmt.Fprintf(w, "Strategy:       %s\n\n", result.Strategy)
+		fmt.Fprintf(w, "Budget Allocation:\n")
+
+		width := len("Over-allocated:")
+		for _, category := range result.Categories {
+			width = max(width, len(category.Name)+1)
+		}
+
+		for _, category := range result.Categories {
+			fixed := ""
+			if category.Fixed {
+				fixed = ", fixed"
+			}
+			fmt.Fprintf(w, "%-*s €%s (%.1f%%%s)\n", width, category.Name+":", category.Amount, category.Percentage, fixed)
+		}
+
+		fmt.Fprintf(w, "\n%-*s €%s (%.1f%%)\n", width, "Total:", result.Total, result.TotalPercentage)
+		switch result.Status {
+		case finance.Unallocated:
+			fmt.Fprintf(w, "%-*s €%s\n", width, "Unallocated:", result.Residual)
+		case finance.OverAllocated:
+			fmt.Fprintf(w, "%-*s €%s\n", width, "Over-allocated:", result.Residual.Neg())
+		}
+
+		if len(result.Groups) > 0 {
+			fmt.Fprintf(w, "\nGroups:\n")
+			for _, group := range result.Groups {
+				fmt.Fprintf(w, "%-*s €%s (%.1f%%", width, string(group.Group)+":", group.Amount, group.Percentage)
+				if group.Target > 0 {
+					fmt.Fprintf(w, ", target %.0f%%", group.Target)
+				}
+				fmt.Fprintf(w, ")\n")
+			}
+		}
+
+		if len(result.SinkingFunds) > 0 {
+			printSinkingFunds(w, result)
+		}
+	})
+
+	// A zero-based budget must assign every euro of the income
+	if result.Strategy == finance.ZeroBased && result.Status != finance.Balanced {
+		os.Exit(1)
+	}
+}
+
+// printSinkingFunds writes the set-aside of each sinking fund and its projected balance
+func printSinkingFunds(w io.Writer, result finance.BudgetResult) {
+	fmt.Fprintf(w, "\nSinking Funds:\n")
+	for _, fund := range result.SinkingFunds {
+		due := make([]string, len(fund.DueMonths))
+		for i, month := range fund.DueMonths {
+			due[i] = month.String()[:3]
+		}
+		fmt.Fprintf(w, "%s: €%s %s, due %s\n", fund.Name, fund.Amount, fund.Frequency, strings.Join(due, ", "))
+		fmt.Fprintf(w, "  Monthly set-aside: €%s\n", fund.MonthlySetAside)
+		if fund.CatchUpSetAside != fund.MonthlySetAside {
+			fmt.Fprintf(w, "  Catch-up set-aside: €%s until the next due date\n", fund.CatchUpSetAside)
+		}
+		if fund.Shortfall.IsPositive() {
+			fmt.Fprintf(w, "  Shortfall: €%s, first in %s\n", fund.Shortfall, fund.FirstShortfall)
+		}
+	}
+
+	fmt.Fprintf(w, "\nProjected Balance:\n")
+	fmt.Fprintf(w, "Month")
+	for _, fund := range result.SinkingFunds {
+		fmt.Fprintf(w, "\t%s", fund.Name)
+	}
+	fmt.Fprintln(w)
+
+	// The schedule lists every month of one fund before the next fund
+	months := len(result.SinkingSchedule) / len(result.SinkingFunds)
+	for m := range months {
+		fmt.Fprintf(w, "%s", result.SinkingSchedule[m].Month)
+		for f := range result.SinkingFunds {
+			row := result.SinkingSchedule[f*months+m]
+			fmt.Fprintf(w, "\t€%s", row.Balance)
+			if row.Shortfall.IsPositive() {
+				fmt.Fprintf(w, " (short €%s)", row.Shortfall)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func handleBudgetActual(args []string) {
+	actualCmd := flag.NewFlagSet("budget actual", flag.ExitOnError)
+
+	var (
+		plan      budgetPlan
+		statement string
+		columns   finance.StatementInput
+		separator string
+		rules     ruleList
+		rulesFile string
+		month     string
+	)
+
+	plan.register(actualCmd)
+	actualCmd.StringVar(&statement, "statement", "", "Bank statement file: .csv, .ofx, .qfx, .qif or CAMT.053 .xml")
+	actualCmd.StringVar(&columns.DateColumn, "date-column", "date", "Header of the booking date column")
+	actualCmd.StringVar(&columns.PayeeColumn, "payee-column", "payee", "Header of the payee column")
+	actualCmd.StringVar(&columns.MemoColumn, "memo-column", "", "Header of the memo column (default: memo or description)")
+	actualCmd.StringVar(&columns.CurrencyColumn, "currency-column", "currency", "Header of the currency column")
+	actualCmd.StringVar(&columns.Currency, "currency", "EUR", "Currency of statements that do not state one")
+	actualCmd.StringVar(&columns.AmountColumn, "amount-column", "amount", "Header of the amount column")
+	actualCmd.StringVar(&columns.DateFormat, "date-format", "", "Layout of the CSV or QIF dates, as a Go time layout (e.g., 02/01/2006; default: 2006-01-02 for CSV, month first for QIF)")
+	actualCmd.BoolVar(&columns.DecimalComma, "decimal-comma", false, "Amounts are written as 1.234,56")
+	actualCmd.StringVar(&separator, "separator", ",", "Field separator of the statement")
+	actualCmd.Var(&rules, "rule", "Categorization rule as category=keyword or category=/payee pattern/ (repeatable)")
+	actualCmd.StringVar(&rulesFile, "rules-file", "", "CSV file of category,match rules")
+	actualCmd.StringVar(&month, "month", "", "Month to compare (YYYY-MM, default: the month of the latest transaction)")
+	actualCmd.StringVar(&format, "format", format, formatUsage)
+
+	if err := actualCmd.Parse(args); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if actualCmd.NArg() > 0 {
+		fmt.Printf("Unknown arguments: %s\n", strings.Join(actualCmd.Args(), " "))
+		actualCmd.PrintDefaults()
+		os.Exit(1)
+	}
+
+	if statement == "" {
+		fmt.Println("--statement is required")
+		actualCmd.PrintDefaults()
+		os.Exit(1)
+	}
+	if len([]rune(separator)) != 1 {
+		fmt.Printf("invalid separator %q, expected a single character\n", separator)
+		os.Exit(1)
+	}
+	columns.Separator = []rune(separator)[0]
+
+	planned, err := finance.AllocateBudget(plan.input(actualCmd))
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	transactions, err := finance.LoadStatement(statement, columns)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	selected := []finance.CategoryRule(rules)
+	if rulesFile != "" {
+		loaded, err := finance.LoadCategoryRules(rulesFile)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		selected = append(loaded, selected...)
 	}
 
-	fmt.Printf("Monthly Income: €%.2f\n\n", result.Income)
-	fmt.Printf("Budget Allocation:\n")
+	input := finance.BudgetActualInput{
+		Plan:         planned,
+		Transactions: transactions,
+		Rules:        selected,
+	}
+	if month != "" {
+		if input.Month, err = time.Parse("2006-01", month); err != nil {
+			fmt.Printf("invalid month %q, expected YYYY-MM\n", month)
+			os.Exit(1)
+		}
+	}
 
-	for _, category := range result.Categories {
-		fmt.Printf("%-15s €%.2f (%.1f%%)\n", category.Name+":", category.Amount, category.Percentage)
+	result, err := finance.CompareBudget(input)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
 
-	fmt.Printf("\nTotal:         €%.2f (%.1f%%)\n", result.Total, result.TotalPercentage)
+	render(result, func(w io.Writer) {
+		fmt.Fprintf(w, "Budget vs Actual: %s\n\n", result.Month)
+
+		width := len("Uncategorized")
+		for _, category := range result.Categories {
+			width = max(width, len(category.Category))
+		}
+
+		fmt.Fprintf(w, "%-*s %12s %12s %12s %9s\n", width, "Category", "Planned", "Actual", "Variance", "Variance%")
+		for _, category := range result.Categories {
+			fmt.Fprintf(w, "%-*s %12s %12s %12s %8.1f%%\n", width, category.Category,
+				"€"+category.Planned.String(), "€"+category.Actual.String(), "€"+category.Variance.String(), category.VariancePercentage)
+		}
+		if !result.Uncategorized.IsZero() {
+			fmt.Fprintf(w, "%-*s %12s %12s\n", width, "Uncategorized", "", "€"+result.Uncategorized.String())
+		}
+		fmt.Fprintf(w, "\n%-*s %12s %12s %12s %8.1f%%\n", width, "Total",
+			"€"+result.Planned.String(), "€"+result.Actual.String(), "€"+result.Variance.String(), result.VariancePercentage)
+
+		if len(result.UncategorizedTransactions) > 0 {
+			fmt.Fprintf(w, "\nUncategorized transactions:\n")
+			for _, transaction := range result.UncategorizedTransactions {
+				fmt.Fprintf(w, "%s  %-30s %s %s\n", transaction.Date.Format("2006-01-02"), transaction.Payee, transaction.Amount, transaction.Currency)
+			}
+		}
+	})
 }
 
 func handleHelp() {
@@ -334,13 +1432,22 @@ func handleUnknownCommand(command string) {
 }
 
 func main() {
-	if len(os.Args) < 2 {
+	globalCmd := flag.NewFlagSet("finz", flag.ExitOnError)
+	globalCmd.StringVar(&format, "format", format, formatUsage)
+	globalCmd.Usage = internal.PrintUsage
+
+	if err := globalCmd.Parse(os.Args[1:]); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	if globalCmd.NArg() < 1 {
 		internal.PrintUsage()
 		os.Exit(1)
 	}
 
-	command := os.Args[1]
-	args := os.Args[2:]
+	command := globalCmd.Arg(0)
+	args := globalCmd.Args()[1:]
 
 	switch command {
 	case "invest":
@@ -351,6 +1458,8 @@ func main() {
 		handleSavings(args)
 	case "retirement":
 		handleRetirement(args)
+	case "portfolio":
+		handlePortfolio(args)
 	case "currency":
 		handleCurrency(args)
 	case "budget":
@@ -1,3 +1,3 @@
-module finz
+module github.com/FilippoVissani/finz
 
 go 1.24
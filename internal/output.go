@@ -0,0 +1,177 @@
+package internal
+
+import (
+	"encoding"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format selects how a command result is written
+type Format string
+
+const (
+	FormatTable Format = "table" // Human-readable layout of each command
+	FormatJSON  Format = "json"  // Result struct encoded with its json field names
+	FormatCSV   Format = "csv"   // Summary row, then one block per table of the result
+)
+
+// ParseFormat returns the output format with the given name
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatTable, FormatJSON, FormatCSV:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown output format: %s (expected table, json or csv)", name)
+	}
+}
+
+// Render writes a result struct in the given format. The table format delegates
+// to the human-readable layout of the command.
+func Render(w io.Writer, format Format, result any, table func(w io.Writer)) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result)
+	case FormatCSV:
+		return renderCSV(w, result)
+	case FormatTable:
+		table(w)
+		return nil
+	default:
+		return fmt.Errorf("unknown output format: %s", format)
+	}
+}
+
+// renderCSV writes the scalar fields of the result as a header and a value row.
+// Each non-empty slice of structs follows as its own table, separated by an empty line.
+func renderCSV(w io.Writer, result any) error {
+	value := reflect.Indirect(reflect.ValueOf(result))
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("cannot render %T as CSV", result)
+	}
+
+	writer := csv.NewWriter(w)
+	header, row := []string{}, []string{}
+	tables := []reflect.Value{}
+
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		name, ok := fieldName(field)
+		if !ok {
+			continue
+		}
+		if isTable(value.Field(i)) {
+			tables = append(tables, value.Field(i))
+			continue
+		}
+		header = append(header, name)
+		row = append(row, formatValue(value.Field(i)))
+	}
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.Write(row); err != nil {
+		return err
+	}
+
+	for _, table := range tables {
+		if table.Len() == 0 {
+			continue
+		}
+		writer.Flush()
+		if _, err := io.WriteString(w, "\n"); err != nil {
+			return err
+		}
+		if err := writeTable(writer, table); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeTable writes a slice of structs with a header row of its field names
+func writeTable(writer *csv.Writer, table reflect.Value) error {
+	elemType := table.Type().Elem()
+
+	header := []string{}
+	for i := 0; i < elemType.NumField(); i++ {
+		if name, ok := fieldName(elemType.Field(i)); ok {
+			header = append(header, name)
+		}
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for i := 0; i < table.Len(); i++ {
+		row := []string{}
+		for j := 0; j < elemType.NumField(); j++ {
+			if _, ok := fieldName(elemType.Field(j)); ok {
+				row = append(row, formatValue(table.Index(i).Field(j)))
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fieldName returns the json name of an exported field
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() {
+		return "", false
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	default:
+		return name, true
+	}
+}
+
+// isTable reports whether the value is a slice of structs
+func isTable(value reflect.Value) bool {
+	return value.Kind() == reflect.Slice && value.Type().Elem().Kind() == reflect.Struct
+}
+
+// formatValue formats a scalar value for a CSV cell
+func formatValue(value reflect.Value) string {
+	if date, ok := value.Interface().(time.Time); ok && date.IsZero() {
+		return "" // Unknown date
+	}
+	if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		if err != nil {
+			return ""
+		}
+		return string(text)
+	}
+
+	switch value.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	case reflect.Slice:
+		parts := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			parts = append(parts, formatValue(value.Index(i)))
+		}
+		return strings.Join(parts, ";")
+	default:
+		return fmt.Sprint(value.Interface())
+	}
+}
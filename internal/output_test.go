@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/FilippoVissani/finz/pkg/finance"
+)
+
+func TestParseFormat(t *testing.T) {
+	for _, name := range []string{"table", "json", "csv", "JSON"} {
+		if _, err := ParseFormat(name); err != nil {
+			t.Errorf("ParseFormat(%q) returned error: %v", name, err)
+		}
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestRender(t *testing.T) {
+	result, err := finance.CalculateLoan(finance.LoanInput{Principal: finance.NewMoney(1200), Rate: 0, Years: 1, Monthly: true, ToMonth: 2})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("JSON", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Render(&buf, FormatJSON, result, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		var decoded map[string]any
+		if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+			t.Fatalf("Invalid JSON: %v", err)
+		}
+		if decoded["monthly_payment"] != 100.0 {
+			t.Errorf("monthly_payment = %v, want 100", decoded["monthly_payment"])
+		}
+		details, ok := decoded["monthly_details"].([]any)
+		if !ok || len(details) != 2 {
+			t.Fatalf("monthly_details = %v, want 2 rows", decoded["monthly_details"])
+		}
+		if details[1].(map[string]any)["remaining_balance"] != 1000.0 {
+			t.Errorf("remaining_balance = %v, want 1000", details[1].(map[string]any)["remaining_balance"])
+		}
+	})
+
+	t.Run("CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := Render(&buf, FormatCSV, result, nil); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		sections := strings.Split(strings.TrimSpace(buf.String()), "\n\n")
+		if len(sections) != 2 {
+			t.Fatalf("Expected a summary and one table, got %d sections:\n%s", len(sections), buf.String())
+		}
+
+		summary := strings.Split(sections[0], "\n")
+		if !strings.HasPrefix(summary[0], "principal,method,monthly_payment,") {
+			t.Errorf("Summary header = %q", summary[0])
+		}
+		if !strings.HasPrefix(summary[1], "1200.00,french,100.00,") {
+			t.Errorf("Summary row = %q", summary[1])
+		}
+
+		rows := strings.Split(sections[1], "\n")
+		if rows[0] != "month,rate,payment,principal_payment,interest_payment,extra_payment,remaining_balance" {
+			t.Errorf("Table header = %q", rows[0])
+		}
+		if len(rows) != 3 || rows[2] != "2,0,100.00,100.00,0.00,0.00,1000.00" {
+			t.Errorf("Table rows = %q", rows[1:])
+		}
+	})
+
+	t.Run("Table", func(t *testing.T) {
+		var buf bytes.Buffer
+		table := func(w io.Writer) { _, _ = io.WriteString(w, "human output\n") }
+		if err := Render(&buf, FormatTable, result, table); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if buf.String() != "human output\n" {
+			t.Errorf("Table output = %q", buf.String())
+		}
+	})
+
+	t.Run("Not a struct", func(t *testing.T) {
+		if err := Render(io.Discard, FormatCSV, 42, nil); err == nil {
+			t.Error("Expected an error for a non-struct result")
+		}
+	})
+}
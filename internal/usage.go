@@ -5,14 +5,19 @@ import "fmt"
 func PrintUsage() {
 	fmt.Println("Finz - Financial Calculator CLI")
 	fmt.Println("\nUsage:")
-	fmt.Println("  finz <command> [options]")
+	fmt.Println("  finz [--format table|json|csv] <command> [options]")
 	fmt.Println("\nAvailable Commands:")
-	fmt.Println("  invest      - Calculate investment growth with taxes and inflation")
+	fmt.Println("  invest      - Calculate investment growth with recurring contributions, fees, taxes and inflation")
 	fmt.Println("  loan        - Calculate loan or mortgage payments")
 	fmt.Println("  savings     - Calculate savings with regular deposits")
 	fmt.Println("  retirement  - Calculate retirement savings and withdrawals")
+	fmt.Println("  portfolio   - Project a multi-asset portfolio with target weights and rebalancing")
 	fmt.Println("  currency    - Convert between currencies")
-	fmt.Println("  budget      - Allocate budget based on percentages")
+	fmt.Println("  currency convert-file - Convert the amounts of a CSV file to one currency")
+	fmt.Println("  budget      - Allocate budget by percentages or a 50/30/20, zero-based or pay-yourself-first template, with sinking funds")
+	fmt.Println("  budget actual - Compare a budget with the spending of a bank statement")
 	fmt.Println("  help        - Show this help message")
+	fmt.Println("\nGlobal Options:")
+	fmt.Println("  --format    - Output format: table (default), json or csv")
 	fmt.Println("\nRun 'finz <command> --help' for more information on a command.")
 }
@@ -0,0 +1,323 @@
+package finance
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReturnFrequency is the period of the returns of a history
+type ReturnFrequency string
+
+const (
+	AnnualReturns  ReturnFrequency = "annual"
+	MonthlyReturns ReturnFrequency = "monthly"
+)
+
+// HistoricalReturn is the market return and the inflation of a year or a month
+type HistoricalReturn struct {
+	Period    string  // YYYY for annual returns, YYYY-MM for monthly returns
+	Return    float64 // In percent
+	Inflation float64 // In percent, over the same period
+}
+
+// ReturnHistory is a series of consecutive historical returns
+type ReturnHistory struct {
+	Frequency    ReturnFrequency
+	Returns      []HistoricalReturn
+	HasInflation bool // Without it the inflation of the plan applies
+}
+
+// BacktestInput represents the input parameters for a backtest. The plan runs over
+// every window of its duration in the history; its annual yield is not used.
+type BacktestInput struct {
+	Plan    InvestmentInput
+	History ReturnHistory
+}
+
+// BacktestWindow is the outcome of the plan started at one point of the history
+type BacktestWindow struct {
+	Start            string  `json:"start"`
+	End              string  `json:"end"`
+	FinalValue       Money   `json:"final_value"` // Nominal value after tax
+	RealValue        Money   `json:"real_value"`
+	AnnualizedReturn float64 `json:"annualized_return"` // Of the market over the window, in percent
+	MaxDrawdown      float64 `json:"max_drawdown"`      // Largest fall of the market from a peak, in percent
+}
+
+// BacktestResult represents the output of a backtest
+type BacktestResult struct {
+	Frequency          ReturnFrequency  `json:"frequency"`
+	Years              int              `json:"years"`
+	TotalInvested      Money            `json:"total_invested"`
+	BestStart          string           `json:"best_start"`
+	BestValue          Money            `json:"best_value"`
+	MedianStart        string           `json:"median_start"`
+	MedianValue        Money            `json:"median_value"`
+	WorstStart         string           `json:"worst_start"`
+	WorstValue         Money            `json:"worst_value"`
+	WorstDrawdown      float64          `json:"worst_drawdown"`
+	WorstDrawdownStart string           `json:"worst_drawdown_start"`
+	Windows            []BacktestWindow `json:"windows"` // In chronological order of the start
+}
+
+// Validate checks the plan and that the history covers its duration at least once
+func (input BacktestInput) Validate() error {
+	if err := input.Plan.Validate(); err != nil {
+		return err
+	}
+	if input.Plan.Years < 1 {
+		return invalidInput("Years", "a backtest needs a duration of at least one year")
+	}
+
+	periodsPerYear, ok := returnPeriodsPerYear[input.History.Frequency]
+	if !ok {
+		return invalidInput("Frequency", "unknown return frequency: %s (expected annual or monthly)", input.History.Frequency)
+	}
+	if len(input.History.Returns) < input.Plan.Years*periodsPerYear {
+		return invalidInput("History", "the history has %d %s returns, fewer than the %d years of the plan", len(input.History.Returns), input.History.Frequency, input.Plan.Years)
+	}
+	for _, r := range input.History.Returns {
+		if r.Return < -100 {
+			return invalidInput("History", "the return of %s is below -100%%", r.Period)
+		}
+		if r.Inflation <= -100 {
+			return invalidInput("History", "the inflation of %s must be greater than -100%%", r.Period)
+		}
+	}
+	return nil
+}
+
+// returnPeriodsPerYear maps each frequency to its number of returns in a year
+var returnPeriodsPerYear = map[ReturnFrequency]int{AnnualReturns: 1, MonthlyReturns: 12}
+
+// Backtest runs the plan over every rolling window of the history and reports the
+// best, the median and the worst final value, and the worst drawdown
+func Backtest(input BacktestInput) (BacktestResult, error) {
+	if err := input.Validate(); err != nil {
+		return BacktestResult{}, err
+	}
+
+	history := input.History
+	periodsPerYear := returnPeriodsPerYear[history.Frequency]
+	length := input.Plan.Years * periodsPerYear
+
+	result := BacktestResult{
+		Frequency: history.Frequency,
+		Years:     input.Plan.Years,
+		Windows:   []BacktestWindow{},
+	}
+
+	for start := 0; start+length <= len(history.Returns); start++ {
+		window := history.Returns[start : start+length]
+		projection := projectInvestment(input.Plan, historicalMarket(window, periodsPerYear, history.HasInflation, input.Plan.Inflation))
+		result.TotalInvested = projection.TotalInvested
+
+		growth := 1.0
+		for _, r := range window {
+			growth *= 1 + r.Return/100
+		}
+
+		result.Windows = append(result.Windows, BacktestWindow{
+			Start:            window[0].Period,
+			End:              window[len(window)-1].Period,
+			FinalValue:       projection.NetFutureValue,
+			RealValue:        projection.RealValue,
+			AnnualizedReturn: (math.Pow(growth, 1/float64(input.Plan.Years)) - 1) * 100,
+			MaxDrawdown:      maxDrawdown(window),
+		})
+	}
+
+	ranked := slices.Clone(result.Windows)
+	slices.SortStableFunc(ranked, func(a, b BacktestWindow) int { return a.FinalValue.Cmp(b.FinalValue) })
+	best, median, worst := ranked[len(ranked)-1], ranked[(len(ranked)-1)/2], ranked[0]
+	result.BestStart, result.BestValue = best.Start, best.FinalValue
+	result.MedianStart, result.MedianValue = median.Start, median.FinalValue
+	result.WorstStart, result.WorstValue = worst.Start, worst.FinalValue
+
+	for _, window := range result.Windows {
+		if window.MaxDrawdown > result.WorstDrawdown || result.WorstDrawdownStart == "" {
+			result.WorstDrawdown, result.WorstDrawdownStart = window.MaxDrawdown, window.Start
+		}
+	}
+
+	return result, nil
+}
+
+// historicalMarket replays a window of the history. Annual returns compound monthly at
+// the equivalent rate; without inflation data the plan inflation applies.
+func historicalMarket(window []HistoricalReturn, periodsPerYear int, hasInflation bool, inflation float64) marketPath {
+	months := 12 / periodsPerYear
+
+	// Price level at the end of each period of the window
+	prices := make([]float64, len(window))
+	level := 1.0
+	for i, r := range window {
+		rate := r.Inflation
+		if !hasInflation {
+			rate = (math.Pow(1+inflation/100, 1/float64(periodsPerYear)) - 1) * 100
+		}
+		level *= 1 + rate/100
+		prices[i] = level
+	}
+
+	return marketPath{
+		growth: func(month int) float64 {
+			return math.Pow(1+window[month/months].Return/100, 1/float64(months))
+		},
+		priceLevel: func(year int) float64 {
+			return prices[year*periodsPerYear-1]
+		},
+	}
+}
+
+// maxDrawdown returns the largest fall in percent of the cumulated returns from a peak
+func maxDrawdown(window []HistoricalReturn) float64 {
+	value, peak, drawdown := 1.0, 1.0, 0.0
+	for _, r := range window {
+		value *= 1 + r.Return/100
+		peak = max(peak, value)
+		drawdown = max(drawdown, (1-value/peak)*100)
+	}
+	return drawdown
+}
+
+// LoadReturnHistory reads a CSV file of historical returns
+func LoadReturnHistory(path string) (ReturnHistory, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return ReturnHistory{}, err
+	}
+	defer file.Close()
+
+	return ParseReturnHistory(file)
+}
+
+// ParseReturnHistory reads a CSV file of historical returns with a header row. The
+// period column (year, month, date or period) holds years such as 2008 for annual
+// returns, or months such as 2008-09 for monthly returns. The return column and the
+// optional inflation column are in percent. The periods must be consecutive.
+func ParseReturnHistory(r io.Reader) (ReturnHistory, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return ReturnHistory{}, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return ReturnHistory{}, err
+	}
+
+	periodColumn := -1
+	for _, name := range []string{"year", "month", "date", "period"} {
+		if periodColumn = columnIndex(header, name, ""); periodColumn >= 0 {
+			break
+		}
+	}
+	returnColumn := columnIndex(header, "return", "")
+	inflationColumn := columnIndex(header, "inflation", "")
+	if periodColumn < 0 || returnColumn < 0 {
+		return ReturnHistory{}, fmt.Errorf("CSV header needs a year, month, date or period column and a return column, got %s", strings.Join(header, ","))
+	}
+
+	history := ReturnHistory{Returns: []HistoricalReturn{}, HasInflation: inflationColumn >= 0}
+	var previous time.Time
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return ReturnHistory{}, err
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if periodColumn >= len(record) || returnColumn >= len(record) || (history.HasInflation && inflationColumn >= len(record)) {
+			return ReturnHistory{}, fmt.Errorf("line %d: missing period, return or inflation", line)
+		}
+
+		period, frequency, err := parseReturnPeriod(record[periodColumn])
+		if err != nil {
+			return ReturnHistory{}, fmt.Errorf("line %d: %w", line, err)
+		}
+		if history.Frequency == "" {
+			history.Frequency = frequency
+		} else if frequency != history.Frequency {
+			return ReturnHistory{}, fmt.Errorf("line %d: %s mixes %s and %s returns", line, strings.TrimSpace(record[periodColumn]), history.Frequency, frequency)
+		} else if next := nextReturnPeriod(previous, frequency); !period.Equal(next) {
+			return ReturnHistory{}, fmt.Errorf("line %d: expected %s after %s, got %s", line, formatReturnPeriod(next, frequency), formatReturnPeriod(previous, frequency), formatReturnPeriod(period, frequency))
+		}
+		previous = period
+
+		value := HistoricalReturn{Period: formatReturnPeriod(period, frequency)}
+		if value.Return, err = parsePercent(record[returnColumn]); err != nil {
+			return ReturnHistory{}, fmt.Errorf("line %d: invalid return %q", line, record[returnColumn])
+		}
+		if history.HasInflation {
+			if value.Inflation, err = parsePercent(record[inflationColumn]); err != nil {
+				return ReturnHistory{}, fmt.Errorf("line %d: invalid inflation %q", line, record[inflationColumn])
+			}
+		}
+		history.Returns = append(history.Returns, value)
+	}
+
+	if len(history.Returns) == 0 {
+		return ReturnHistory{}, fmt.Errorf("no returns found")
+	}
+	return history, nil
+}
+
+// parseReturnPeriod reads a year, a month (2008-09) or a date (2008-09-30) and returns
+// the start of the period
+func parseReturnPeriod(value string) (time.Time, ReturnFrequency, error) {
+	value = strings.TrimSpace(value)
+	if year, err := time.Parse("2006", value); err == nil {
+		return year, AnnualReturns, nil
+	}
+	if month, err := time.Parse(monthLayout, value); err == nil {
+		return month, MonthlyReturns, nil
+	}
+	if date, err := time.Parse(dateLayout, value); err == nil {
+		return time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC), MonthlyReturns, nil
+	}
+	return time.Time{}, "", fmt.Errorf("invalid period %q, expected YYYY, YYYY-MM or YYYY-MM-DD", value)
+}
+
+func nextReturnPeriod(period time.Time, frequency ReturnFrequency) time.Time {
+	if frequency == AnnualReturns {
+		return period.AddDate(1, 0, 0)
+	}
+	return period.AddDate(0, 1, 0)
+}
+
+func formatReturnPeriod(period time.Time, frequency ReturnFrequency) string {
+	if frequency == AnnualReturns {
+		return period.Format("2006")
+	}
+	return period.Format(monthLayout)
+}
+
+// parsePercent reads a percentage such as 7.5 or -12%, rejecting NaN and infinities
+func parsePercent(value string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(value), "%"), 64)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(percent) || math.IsInf(percent, 0) {
+		return 0, fmt.Errorf("%q is not a finite number", value)
+	}
+	return percent, nil
+}
@@ -0,0 +1,203 @@
+package finance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBacktest(t *testing.T) {
+	history, err := LoadReturnHistory("testdata/returns_annual.csv")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		plan           InvestmentInput
+		expectedBest   float64
+		expectedMedian float64
+		expectedWorst  float64
+	}{
+		{
+			name:           "Lump sum",
+			plan:           InvestmentInput{Principal: NewMoney(1000), Years: 2},
+			expectedBest:   1250, // 2002-2003: +25%, 0%
+			expectedMedian: 1000, // 2001-2002: -20%, +25%
+			expectedWorst:  880,  // 2000-2001: +10%, -20%
+		},
+		{
+			name:           "Tax on the gains only",
+			plan:           InvestmentInput{Principal: NewMoney(1000), TaxRate: 26, Years: 2},
+			expectedBest:   1185,
+			expectedMedian: 1000,
+			expectedWorst:  880,
+		},
+		{
+			name:           "Whole history",
+			plan:           InvestmentInput{Principal: NewMoney(1000), Years: 5},
+			expectedBest:   1155,
+			expectedMedian: 1155,
+			expectedWorst:  1155,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Backtest(BacktestInput{Plan: tt.plan, History: history})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if len(result.Windows) != 6-tt.plan.Years {
+				t.Errorf("Got %d windows, want %d", len(result.Windows), 6-tt.plan.Years)
+			}
+			if result.BestValue != NewMoney(tt.expectedBest) || result.MedianValue != NewMoney(tt.expectedMedian) || result.WorstValue != NewMoney(tt.expectedWorst) {
+				t.Errorf("Best, median, worst = %v, %v, %v, want %.2f, %.2f, %.2f",
+					result.BestValue, result.MedianValue, result.WorstValue, tt.expectedBest, tt.expectedMedian, tt.expectedWorst)
+			}
+			if result.WorstDrawdownStart != "2000" || !approximatelyEqual(result.WorstDrawdown, 20, 0.0001) {
+				t.Errorf("Worst drawdown = %.2f%% from %s, want 20%% from 2000", result.WorstDrawdown, result.WorstDrawdownStart)
+			}
+		})
+	}
+
+	t.Run("Windows", func(t *testing.T) {
+		result, err := Backtest(BacktestInput{Plan: InvestmentInput{Principal: NewMoney(1000), Years: 2}, History: history})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.BestStart != "2002" || result.MedianStart != "2001" || result.WorstStart != "2000" {
+			t.Errorf("Best, median, worst start = %s, %s, %s, want 2002, 2001, 2000", result.BestStart, result.MedianStart, result.WorstStart)
+		}
+
+		window := result.Windows[2]
+		if window.Start != "2002" || window.End != "2003" {
+			t.Errorf("Window = %s to %s, want 2002 to 2003", window.Start, window.End)
+		}
+		// Two years of 2% inflation
+		if window.RealValue != NewMoney(1201.46) {
+			t.Errorf("RealValue = %v, want 1201.46", window.RealValue)
+		}
+		if !approximatelyEqual(window.AnnualizedReturn, 11.8034, 0.0001) || window.MaxDrawdown != 0 {
+			t.Errorf("AnnualizedReturn = %.4f%%, MaxDrawdown = %.2f%%, want 11.8034%% and 0%%", window.AnnualizedReturn, window.MaxDrawdown)
+		}
+	})
+
+	t.Run("Contributions", func(t *testing.T) {
+		plan := InvestmentInput{Contribution: NewMoney(100), ContributionFrequency: ContributeYearly, Years: 1}
+		result, err := Backtest(BacktestInput{Plan: plan, History: history})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.TotalInvested != NewMoney(100) || result.BestValue != NewMoney(125) || result.WorstValue != NewMoney(80) {
+			t.Errorf("TotalInvested = %v, best = %v, worst = %v, want 100.00, 125.00 and 80.00", result.TotalInvested, result.BestValue, result.WorstValue)
+		}
+	})
+}
+
+func TestBacktestMonthly(t *testing.T) {
+	var data strings.Builder
+	data.WriteString("month,return\n")
+	for month := range 24 {
+		fmt.Fprintf(&data, "%d-%02d,1\n", 2020+month/12, month%12+1)
+	}
+	history, err := ParseReturnHistory(strings.NewReader(data.String()))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if history.Frequency != MonthlyReturns || history.HasInflation {
+		t.Fatalf("Frequency = %s, HasInflation = %v, want monthly without inflation", history.Frequency, history.HasInflation)
+	}
+
+	result, err := Backtest(BacktestInput{Plan: InvestmentInput{Principal: NewMoney(1000), Inflation: 2, Years: 1}, History: history})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// 1% a month for twelve months, deflated by the 2% inflation of the plan
+	if len(result.Windows) != 13 || result.Windows[12].Start != "2021-01" || result.Windows[12].End != "2021-12" {
+		t.Errorf("Got %d windows, the last from %s to %s, want 13 up to 2021-01 to 2021-12", len(result.Windows), result.Windows[12].Start, result.Windows[12].End)
+	}
+	if result.BestValue != NewMoney(1126.82) || result.WorstValue != NewMoney(1126.82) || result.Windows[0].RealValue != NewMoney(1104.73) {
+		t.Errorf("Best = %v, worst = %v, real = %v, want 1126.82, 1126.82 and 1104.73", result.BestValue, result.WorstValue, result.Windows[0].RealValue)
+	}
+}
+
+func TestBacktestValidation(t *testing.T) {
+	history, err := LoadReturnHistory("testdata/returns_annual.csv")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	invalid := map[string]BacktestInput{
+		"No duration":       {Plan: InvestmentInput{Principal: NewMoney(1000)}, History: history},
+		"History too short": {Plan: InvestmentInput{Principal: NewMoney(1000), Years: 6}, History: history},
+		"No history":        {Plan: InvestmentInput{Principal: NewMoney(1000), Years: 1}},
+		"Invalid plan":      {Plan: InvestmentInput{Principal: NewMoney(-1), Years: 1}, History: history},
+		"Return below -100%": {
+			Plan:    InvestmentInput{Principal: NewMoney(1000), Years: 1},
+			History: ReturnHistory{Frequency: AnnualReturns, Returns: []HistoricalReturn{{Period: "2008", Return: -101}}},
+		},
+	}
+	for name, input := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := Backtest(input)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected a ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseReturnHistory(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected []HistoricalReturn
+		wantErr  string
+	}{
+		{
+			name:     "Dates of monthly returns",
+			data:     "date,return,inflation\n2008-09-30,-9.1%,0.1\n2008-10-31,-16.8%,-0.4\n",
+			expected: []HistoricalReturn{{Period: "2008-09", Return: -9.1, Inflation: 0.1}, {Period: "2008-10", Return: -16.8, Inflation: -0.4}},
+		},
+		{
+			name:     "Columns in another order",
+			data:     "Return,Year\n5.5,2007\n-37,2008\n",
+			expected: []HistoricalReturn{{Period: "2007", Return: 5.5}, {Period: "2008", Return: -37}},
+		},
+		{name: "Missing year", data: "year,return\n2007,5.5\n2009,26.5\n", wantErr: "expected 2008 after 2007"},
+		{name: "Mixed periods", data: "period,return\n2007,5.5\n2008-01,1\n", wantErr: "mixes annual and monthly"},
+		{name: "Invalid return", data: "year,return\n2007,five\n", wantErr: "invalid return"},
+		{name: "NaN return", data: "year,return\n2000,NaN\n", wantErr: "line 2: invalid return"},
+		{name: "Infinite inflation", data: "year,return,inflation\n2000,5,-Inf\n", wantErr: "line 2: invalid inflation"},
+		{name: "No return column", data: "year,value\n2007,5.5\n", wantErr: "CSV header"},
+		{name: "Empty", data: "year,return\n", wantErr: "no returns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history, err := ParseReturnHistory(strings.NewReader(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(history.Returns) != len(tt.expected) {
+				t.Fatalf("Got %d returns, want %d", len(history.Returns), len(tt.expected))
+			}
+			for i, expected := range tt.expected {
+				if history.Returns[i] != expected {
+					t.Errorf("Return %d = %+v, want %+v", i, history.Returns[i], expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,476 @@
+package finance
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// BudgetStrategy selects how the income is split across the categories
+type BudgetStrategy string
+
+const (
+	PercentageBudget  BudgetStrategy = "percentage"         // Each category gets its percentage of the income
+	FiftyThirtyTwenty BudgetStrategy = "50-30-20"           // 50% needs, 30% wants, 20% savings, split within each group
+	ZeroBased         BudgetStrategy = "zero-based"         // Like percentage, but a budget that does not assign every euro is flagged
+	PayYourselfFirst  BudgetStrategy = "pay-yourself-first" // Savings off the top, the rest split by weight
+)
+
+// BudgetGroup is the 50/30/20 group of a category
+type BudgetGroup string
+
+const (
+	GroupNeeds   BudgetGroup = "needs"
+	GroupWants   BudgetGroup = "wants"
+	GroupSavings BudgetGroup = "savings" // Savings and debt repayment
+)
+
+// budgetGroups lists the groups in display order
+var budgetGroups = []BudgetGroup{GroupNeeds, GroupWants, GroupSavings}
+
+// fiftyThirtyTwentyTargets holds the income share of each group in percent
+var fiftyThirtyTwentyTargets = map[BudgetGroup]float64{GroupNeeds: 50, GroupWants: 30, GroupSavings: 20}
+
+// DefaultSavingsRate is the share of income saved first by PayYourselfFirst, in percent
+const DefaultSavingsRate = 20.0
+
+// PercentageBase selects the amount the percentages of a budget apply to
+type PercentageBase string
+
+const (
+	PercentOfRemainder PercentageBase = "remainder" // Income left after the fixed amounts
+	PercentOfIncome    PercentageBase = "income"    // Gross income
+)
+
+// BudgetStatus tells whether the categories add up to the income
+type BudgetStatus string
+
+const (
+	Balanced      BudgetStatus = "balanced"
+	Unallocated   BudgetStatus = "unallocated"    // Part of the income is not allocated
+	OverAllocated BudgetStatus = "over-allocated" // The categories exceed the income
+)
+
+// BudgetInput represents the input parameters for budget allocation
+type BudgetInput struct {
+	Income         Money
+	Categories     []BudgetCategoryInput // Empty uses DefaultBudgetCategories
+	Strategy       BudgetStrategy        // Default: PercentageBudget
+	SavingsRate    float64               // Share saved first by PayYourselfFirst, in percent (default: DefaultSavingsRate)
+	PercentageBase PercentageBase        // Default: PercentOfRemainder
+	SinkingFunds   []SinkingFundInput    // Each adds a fixed category with its monthly set-aside
+	Month          time.Time             // First month of the sinking fund projection
+}
+
+// BudgetCategoryInput is a named category with either a fixed amount or a share of
+// the income. With the 50-30-20 and pay-yourself-first strategies the percentage is a
+// weight relative to the other categories of the same share: the group, the savings
+// or the rest of the income.
+type BudgetCategoryInput struct {
+	Name       string
+	Percentage float64
+	Amount     Money // Fixed amount, used instead of the percentage when Fixed is set
+	Fixed      bool
+	Group      BudgetGroup
+}
+
+// BudgetCategory represents a single budget category
+type BudgetCategory struct {
+	Name       string      `json:"name"`
+	Group      BudgetGroup `json:"group,omitempty"`
+	Fixed      bool        `json:"fixed"`
+	Amount     Money       `json:"amount"`
+	Percentage float64     `json:"percentage"` // Share of the income
+}
+
+// BudgetGroupTotal sums the categories of a group
+type BudgetGroupTotal struct {
+	Group      BudgetGroup `json:"group"`
+	Amount     Money       `json:"amount"`
+	Percentage float64     `json:"percentage"` // Share of the income
+	Target     float64     `json:"target"`     // Target share of the 50-30-20 strategy, 0 otherwise
+}
+
+// BudgetResult represents the output of budget allocation
+type BudgetResult struct {
+	Income          Money              `json:"income"`
+	Strategy        BudgetStrategy     `json:"strategy"`
+	PercentageBase  PercentageBase     `json:"percentage_base"`
+	Categories      []BudgetCategory   `json:"categories"`
+	Groups          []BudgetGroupTotal `json:"groups"`
+	FixedTotal      Money              `json:"fixed_total"`
+	Total           Money              `json:"total"`
+	TotalPercentage float64            `json:"total_percentage"`
+	Residual        Money              `json:"residual"` // Income left unallocated, negative when over-allocated
+	Status          BudgetStatus       `json:"status"`
+	Warning         string             `json:"warning"` // Describes a non-zero residual
+	SinkingFunds    []SinkingFund      `json:"sinking_funds"`
+	SinkingSchedule []SinkingFundMonth `json:"sinking_schedule"` // Projected balance of each fund month by month
+}
+
+// DefaultBudgetCategories returns the default template of eight categories
+func DefaultBudgetCategories() []BudgetCategoryInput {
+	return []BudgetCategoryInput{
+		{Name: "Housing", Percentage: 30, Group: GroupNeeds},
+		{Name: "Food", Percentage: 15, Group: GroupNeeds},
+		{Name: "Transportation", Percentage: 10, Group: GroupNeeds},
+		{Name: "Utilities", Percentage: 5, Group: GroupNeeds},
+		{Name: "Healthcare", Percentage: 5, Group: GroupNeeds},
+		{Name: "Debt Repayment", Percentage: 10, Group: GroupSavings},
+		{Name: "Savings", Percentage: 15, Group: GroupSavings},
+		{Name: "Discretionary", Percentage: 10, Group: GroupWants},
+	}
+}
+
+// Validate checks that the income and the percentages are not negative, that every
+// category has a unique name and that the categories fit the strategy
+func (input BudgetInput) Validate() error {
+	if input.Income.IsNegative() {
+		return invalidInput("Income", "income must not be negative")
+	}
+
+	switch input.Strategy {
+	case "", PercentageBudget, FiftyThirtyTwenty, ZeroBased, PayYourselfFirst:
+	default:
+		return invalidInput("Strategy", "unknown budget strategy: %s (expected percentage, 50-30-20, zero-based or pay-yourself-first)", input.Strategy)
+	}
+	if err := validatePercentage("SavingsRate", input.SavingsRate, 0, 100); err != nil {
+		return err
+	}
+	switch input.PercentageBase {
+	case "", PercentOfRemainder, PercentOfIncome:
+	default:
+		return invalidInput("PercentageBase", "unknown percentage base: %s (expected remainder or income)", input.PercentageBase)
+	}
+
+	seen := map[string]bool{}
+	hasSavings := false
+	for _, category := range input.Categories {
+		name := strings.TrimSpace(category.Name)
+		if name == "" {
+			return invalidInput("Categories", "budget categories need a name")
+		}
+		if seen[strings.ToLower(name)] {
+			return invalidInput("Categories", "duplicate budget category: %s", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		if category.Percentage < 0 {
+			return invalidInput("Percentage", "budget percentages must not be negative")
+		}
+		if category.Fixed && category.Amount.IsNegative() {
+			return invalidInput("Amount", "fixed amount of %s must not be negative", name)
+		}
+		if category.Fixed && category.Percentage != 0 {
+			return invalidInput("Percentage", "category %s has both a fixed amount and a percentage", name)
+		}
+
+		switch category.Group {
+		case GroupNeeds, GroupWants:
+		case GroupSavings:
+			hasSavings = hasSavings || !category.Fixed
+		case "":
+			if input.Strategy == FiftyThirtyTwenty {
+				return invalidInput("Group", "category %s needs a group (needs, wants or savings) for the 50-30-20 strategy", name)
+			}
+		default:
+			return invalidInput("Group", "unknown budget group for %s: %s (expected needs, wants or savings)", name, category.Group)
+		}
+	}
+
+	if input.Strategy == PayYourselfFirst && len(input.Categories) > 0 && !hasSavings {
+		return invalidInput("Group", "the pay-yourself-first strategy needs a percentage category in the savings group")
+	}
+
+	if len(input.SinkingFunds) > 0 {
+		if input.Month.IsZero() {
+			return invalidInput("Month", "sinking funds need the first month of the projection")
+		}
+		if err := (SinkingFundsInput{Funds: input.SinkingFunds, Start: input.Month}).Validate(); err != nil {
+			return err
+		}
+
+		categories := input.Categories
+		if len(categories) == 0 {
+			categories = DefaultBudgetCategories()
+		}
+		for _, fund := range input.SinkingFunds {
+			for _, category := range categories {
+				if strings.EqualFold(strings.TrimSpace(fund.Name), strings.TrimSpace(category.Name)) {
+					return invalidInput("SinkingFunds", "sinking fund %s has the name of a budget category", strings.TrimSpace(fund.Name))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// AllocateBudget splits the income across the budget categories with the chosen
+// strategy. A ZeroBased budget whose categories do not add up to the income is
+// returned with its Status and a Warning naming the amount to assign or to remove.
+func AllocateBudget(input BudgetInput) (BudgetResult, error) {
+	if err := input.Validate(); err != nil {
+		return BudgetResult{}, err
+	}
+
+	categories := input.Categories
+	if len(categories) == 0 {
+		categories = DefaultBudgetCategories()
+	}
+
+	// Each sinking fund sets its monthly share of the yearly cost aside
+	sinking := SinkingFundsResult{Funds: []SinkingFund{}, Schedule: []SinkingFundMonth{}}
+	if len(input.SinkingFunds) > 0 {
+		var err error
+		if sinking, err = PlanSinkingFunds(SinkingFundsInput{Funds: input.SinkingFunds, Start: input.Month}); err != nil {
+			return BudgetResult{}, err
+		}
+		categories = slices.Clone(categories)
+		for i, fund := range sinking.Funds {
+			group := input.SinkingFunds[i].Group
+			if group == "" {
+				group = GroupSavings
+			}
+			categories = append(categories, BudgetCategoryInput{Name: fund.Name, Amount: fund.MonthlySetAside, Fixed: true, Group: group})
+		}
+	}
+
+	strategy := input.Strategy
+	if strategy == "" {
+		strategy = PercentageBudget
+	}
+
+	base := input.PercentageBase
+	if base == "" {
+		base = PercentOfRemainder
+	}
+
+	// Fixed amounts come first, the percentages share what is left
+	amounts := make([]Money, len(categories))
+	percentages := make([]float64, len(categories)) // Share of the income of each category
+	flexible := []int{}
+	fixedTotal := Money{}
+	for i, category := range categories {
+		if category.Fixed {
+			amounts[i] = category.Amount
+			percentages[i] = shareOfIncome(category.Amount, input.Income)
+			fixedTotal = fixedTotal.Add(category.Amount)
+		} else {
+			flexible = append(flexible, i)
+		}
+	}
+
+	available := input.Income
+	if base == PercentOfRemainder {
+		available = MaxMoney(input.Income.Sub(fixedTotal), Money{})
+	}
+
+	if strategy == FiftyThirtyTwenty {
+		allocateFiftyThirtyTwenty(input.Income, categories, amounts, percentages)
+	} else {
+		weights := make([]float64, len(flexible))
+		groups := make([]BudgetGroup, len(flexible))
+		for j, i := range flexible {
+			weights[j] = categories[i].Percentage
+			groups[j] = categories[i].Group
+		}
+
+		var shares []Money
+		var sharePercentages []float64 // Share of the available amount
+		if strategy == PayYourselfFirst {
+			savingsRate := input.SavingsRate
+			if savingsRate == 0 {
+				savingsRate = DefaultSavingsRate
+			}
+			shares, sharePercentages = allocatePayYourselfFirst(available, weights, groups, savingsRate)
+		} else {
+			// Split the amount so that the categories sum exactly to the total
+			shares, sharePercentages = available.Allocate(weights), weights
+		}
+
+		// Percentages of the remainder become shares of the income
+		scale := 1.0
+		if available != input.Income {
+			scale = shareOfIncome(available, input.Income) / 100
+		}
+		for j, i := range flexible {
+			amounts[i] = shares[j]
+			percentages[i] = sharePercentages[j] * scale
+		}
+	}
+
+	result := BudgetResult{
+		Income:          input.Income,
+		Strategy:        strategy,
+		PercentageBase:  base,
+		Categories:      []BudgetCategory{},
+		Groups:          []BudgetGroupTotal{},
+		FixedTotal:      fixedTotal,
+		Status:          Balanced,
+		SinkingFunds:    sinking.Funds,
+		SinkingSchedule: sinking.Schedule,
+	}
+
+	for i, category := range categories {
+		result.Categories = append(result.Categories, BudgetCategory{
+			Name:       strings.TrimSpace(category.Name),
+			Group:      category.Group,
+			Fixed:      category.Fixed,
+			Amount:     amounts[i],
+			Percentage: percentages[i],
+		})
+		result.TotalPercentage += percentages[i]
+	}
+	result.Total = SumMoney(amounts...)
+	result.Residual = input.Income.Sub(result.Total)
+	result.Groups = groupTotals(result.Categories, strategy)
+
+	if result.Residual.IsPositive() {
+		result.Status = Unallocated
+		result.Warning = "Warning: " + result.Residual.String() + " of income is not allocated to a category"
+		if strategy == ZeroBased {
+			result.Warning = "Warning: zero-based budget leaves " + result.Residual.String() + " of income unassigned"
+		}
+	} else if result.Residual.IsNegative() {
+		result.Status = OverAllocated
+		result.Warning = "Warning: the categories exceed the income by " + result.Residual.Neg().String()
+		if strategy == ZeroBased {
+			result.Warning = "Warning: zero-based budget assigns " + result.Residual.Neg().String() + " more than the income"
+		}
+	}
+
+	return result, nil
+}
+
+// allocateFiftyThirtyTwenty splits the income 50/30/20 across the groups, then what
+// the fixed amounts of each group leave across its other categories by their weights
+func allocateFiftyThirtyTwenty(income Money, categories []BudgetCategoryInput, amounts []Money, percentages []float64) {
+	targets := make([]float64, len(budgetGroups))
+	for i, group := range budgetGroups {
+		targets[i] = fiftyThirtyTwentyTargets[group]
+	}
+	groupAmounts := income.Allocate(targets)
+
+	for g, group := range budgetGroups {
+		members := []int{}
+		available := groupAmounts[g]
+		for i, category := range categories {
+			if category.Group != group {
+				continue
+			}
+			if category.Fixed {
+				available = available.Sub(category.Amount)
+			} else {
+				members = append(members, i)
+			}
+		}
+		if len(members) == 0 {
+			continue
+		}
+		available = MaxMoney(available, Money{})
+
+		weights := make([]float64, len(members))
+		for j, i := range members {
+			weights[j] = categories[i].Percentage
+		}
+		shares, normalized := allocateShares(available, weights)
+		for j, i := range members {
+			amounts[i] = shares[j]
+			if available == groupAmounts[g] {
+				percentages[i] = targets[g] * normalized[j] / 100
+			} else {
+				percentages[i] = shareOfIncome(available, income) * normalized[j] / 100
+			}
+		}
+	}
+}
+
+// allocatePayYourselfFirst sets the savings rate aside for the savings categories and
+// splits the rest of the amount across the other categories by their weights. The
+// percentages it returns are shares of the amount.
+func allocatePayYourselfFirst(amount Money, weights []float64, groups []BudgetGroup, savingsRate float64) ([]Money, []float64) {
+	amounts := make([]Money, len(weights))
+	percentages := make([]float64, len(weights))
+
+	savingsAmount := amount.Allocate([]float64{savingsRate})[0]
+	remainder := amount.Sub(savingsAmount)
+
+	savings, others := []int{}, []int{}
+	for i, group := range groups {
+		if group == GroupSavings {
+			savings = append(savings, i)
+		} else {
+			others = append(others, i)
+		}
+	}
+
+	for _, share := range []struct {
+		members []int
+		amount  Money
+		rate    float64
+	}{{savings, savingsAmount, savingsRate}, {others, remainder, 100 - savingsRate}} {
+		memberWeights := make([]float64, len(share.members))
+		for j, i := range share.members {
+			memberWeights[j] = weights[i]
+		}
+		shares, normalized := allocateShares(share.amount, memberWeights)
+		for j, i := range share.members {
+			amounts[i] = shares[j]
+			percentages[i] = share.rate * normalized[j] / 100
+		}
+	}
+
+	return amounts, percentages
+}
+
+// shareOfIncome returns an amount as a percentage of the income, 0 without income
+func shareOfIncome(amount, income Money) float64 {
+	if income.IsZero() {
+		return 0
+	}
+	return amount.Float64() / income.Float64() * 100
+}
+
+// allocateShares splits an amount by relative weights. Weights that are all zero
+// split the amount evenly. It also returns the weights normalized to 100.
+func allocateShares(amount Money, weights []float64) ([]Money, []float64) {
+	total := 0.0
+	for _, weight := range weights {
+		total += weight
+	}
+
+	normalized := make([]float64, len(weights))
+	for i, weight := range weights {
+		if total == 0 {
+			normalized[i] = 100 / float64(len(weights))
+		} else {
+			normalized[i] = weight / total * 100
+		}
+	}
+	return amount.Allocate(normalized), normalized
+}
+
+// groupTotals sums the categories by group, in display order. The 50-30-20 strategy
+// lists every group with its target.
+func groupTotals(categories []BudgetCategory, strategy BudgetStrategy) []BudgetGroupTotal {
+	totals := []BudgetGroupTotal{}
+	for _, group := range budgetGroups {
+		total := BudgetGroupTotal{Group: group}
+		found := false
+		for _, category := range categories {
+			if category.Group == group {
+				total.Amount = total.Amount.Add(category.Amount)
+				total.Percentage += category.Percentage
+				found = true
+			}
+		}
+		if strategy == FiftyThirtyTwenty {
+			total.Target = fiftyThirtyTwentyTargets[group]
+			found = true
+		}
+		if found {
+			totals = append(totals, total)
+		}
+	}
+	return totals
+}
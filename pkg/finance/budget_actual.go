@@ -0,0 +1,234 @@
+package finance
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// monthLayout is the YYYY-MM format of budget months
+const monthLayout = "2006-01"
+
+// CategoryRule assigns the transactions it matches to a budget category
+type CategoryRule struct {
+	Category string
+	Keyword  string         // Case-insensitive text of the payee or memo
+	Payee    *regexp.Regexp // Pattern of the payee
+}
+
+// BudgetActualInput represents the input parameters for comparing a budget with a statement
+type BudgetActualInput struct {
+	Plan         BudgetResult
+	Transactions []Transaction
+	Rules        []CategoryRule // The first matching rule wins
+	Month        time.Time      // Month to compare; zero uses the month of the latest transaction
+}
+
+// BudgetVariance compares the plan of a category with its actual spending
+type BudgetVariance struct {
+	Category           string  `json:"category"`
+	Planned            Money   `json:"planned"`
+	Actual             Money   `json:"actual"`
+	Variance           Money   `json:"variance"`            // Planned minus actual, negative when overspent
+	VariancePercentage float64 `json:"variance_percentage"` // Variance as a share of the plan, 0 without a plan
+	Transactions       int     `json:"transactions"`
+}
+
+// BudgetActualResult represents the output of the planned-versus-actual comparison
+type BudgetActualResult struct {
+	Month                     string           `json:"month"` // YYYY-MM
+	Categories                []BudgetVariance `json:"categories"`
+	Planned                   Money            `json:"planned"`
+	Actual                    Money            `json:"actual"` // All spending, including uncategorized
+	Variance                  Money            `json:"variance"`
+	VariancePercentage        float64          `json:"variance_percentage"`
+	Uncategorized             Money            `json:"uncategorized"` // Spending matched by no rule
+	Received                  Money            `json:"received"`      // Money received matched by no rule
+	UncategorizedTransactions []Transaction    `json:"uncategorized_transactions"`
+}
+
+// Matches reports whether the rule applies to the transaction
+func (rule CategoryRule) Matches(transaction Transaction) bool {
+	if rule.Payee != nil && rule.Payee.MatchString(transaction.Payee) {
+		return true
+	}
+	if rule.Keyword == "" {
+		return false
+	}
+	keyword := strings.ToLower(rule.Keyword)
+	return strings.Contains(strings.ToLower(transaction.Payee), keyword) ||
+		strings.Contains(strings.ToLower(transaction.Memo), keyword)
+}
+
+// Validate checks that there are transactions and that every rule names a category
+// of the plan
+func (input BudgetActualInput) Validate() error {
+	if len(input.Transactions) == 0 {
+		return invalidInput("Transactions", "the statement has no transactions")
+	}
+
+	for _, rule := range input.Rules {
+		if rule.Keyword == "" && rule.Payee == nil {
+			return invalidInput("Rules", "rule for %s needs a keyword or a payee pattern", rule.Category)
+		}
+		if _, ok := planCategory(input.Plan, rule.Category); !ok {
+			return invalidInput("Rules", "rule for %s, which is not a category of the budget", rule.Category)
+		}
+	}
+	return nil
+}
+
+// CompareBudget categorizes the transactions of a month with the rules and compares
+// the spending of each category with the plan. Money received in a category, such as
+// a refund, reduces its spending.
+func CompareBudget(input BudgetActualInput) (BudgetActualResult, error) {
+	if err := input.Validate(); err != nil {
+		return BudgetActualResult{}, err
+	}
+
+	month := input.Month
+	if month.IsZero() {
+		for _, transaction := range input.Transactions {
+			if transaction.Date.After(month) {
+				month = transaction.Date
+			}
+		}
+	}
+	month = time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	result := BudgetActualResult{
+		Month:                     month.Format(monthLayout),
+		Categories:                []BudgetVariance{},
+		Planned:                   input.Plan.Total,
+		UncategorizedTransactions: []Transaction{},
+	}
+	for _, category := range input.Plan.Categories {
+		result.Categories = append(result.Categories, BudgetVariance{Category: category.Name, Planned: category.Amount})
+	}
+
+	for _, transaction := range input.Transactions {
+		if transaction.Date.Year() != month.Year() || transaction.Date.Month() != month.Month() {
+			continue
+		}
+
+		index := -1
+		for _, rule := range input.Rules {
+			if rule.Matches(transaction) {
+				index, _ = planCategory(input.Plan, rule.Category)
+				break
+			}
+		}
+
+		switch {
+		case index >= 0:
+			result.Categories[index].Actual = result.Categories[index].Actual.Sub(transaction.Amount)
+			result.Categories[index].Transactions++
+		case transaction.Amount.IsNegative():
+			result.Uncategorized = result.Uncategorized.Sub(transaction.Amount)
+			result.UncategorizedTransactions = append(result.UncategorizedTransactions, transaction)
+		default:
+			result.Received = result.Received.Add(transaction.Amount)
+		}
+	}
+
+	result.Actual = result.Uncategorized
+	for i := range result.Categories {
+		variance := &result.Categories[i]
+		variance.Variance = variance.Planned.Sub(variance.Actual)
+		variance.VariancePercentage = varianceShare(variance.Variance, variance.Planned)
+		result.Actual = result.Actual.Add(variance.Actual)
+	}
+	result.Variance = result.Planned.Sub(result.Actual)
+	result.VariancePercentage = varianceShare(result.Variance, result.Planned)
+
+	return result, nil
+}
+
+// planCategory returns the index of a category of the plan, case-insensitively
+func planCategory(plan BudgetResult, name string) (int, bool) {
+	for i, category := range plan.Categories {
+		if strings.EqualFold(category.Name, strings.TrimSpace(name)) {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+// varianceShare returns the variance as a percentage of the planned amount
+func varianceShare(variance, planned Money) float64 {
+	if planned.IsZero() {
+		return 0
+	}
+	return variance.Float64() / planned.Float64() * 100
+}
+
+// LoadCategoryRules reads category rules from a CSV file with category,match rows
+func LoadCategoryRules(path string) ([]CategoryRule, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseCategoryRules(file)
+}
+
+// ParseCategoryRules reads category rules in CSV format: a category followed by a
+// keyword, or by a payee pattern between slashes such as /^(coop|conad)/. A header
+// row is allowed, and lines starting with # are comments.
+func ParseCategoryRules(r io.Reader) ([]CategoryRule, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := []CategoryRule{}
+	for i, record := range records {
+		if i == 0 && strings.EqualFold(strings.TrimSpace(record[0]), "category") {
+			continue // Header row
+		}
+		rule, err := newCategoryRule(record[0], record[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// ParseCategoryRule parses a category=match rule, where the match is a keyword or a
+// payee pattern between slashes
+func ParseCategoryRule(value string) (CategoryRule, error) {
+	category, match, found := strings.Cut(value, "=")
+	if !found {
+		return CategoryRule{}, fmt.Errorf("invalid rule %q, expected category=keyword or category=/pattern/", value)
+	}
+	return newCategoryRule(category, match)
+}
+
+func newCategoryRule(category, match string) (CategoryRule, error) {
+	category, match = strings.TrimSpace(category), strings.TrimSpace(match)
+	if category == "" || match == "" {
+		return CategoryRule{}, fmt.Errorf("rules need a category and a keyword or pattern")
+	}
+
+	if len(match) > 1 && strings.HasPrefix(match, "/") && strings.HasSuffix(match, "/") {
+		pattern, err := regexp.Compile(match[1 : len(match)-1])
+		if err != nil {
+			return CategoryRule{}, fmt.Errorf("invalid payee pattern %s: %w", match, err)
+		}
+		return CategoryRule{Category: category, Payee: pattern}, nil
+	}
+	return CategoryRule{Category: category, Keyword: match}, nil
+}
@@ -0,0 +1,151 @@
+package finance
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const statementCSV = `date,payee,description,amount
+2026-09-01,ACME Properties,Rent September,-1000.00
+2026-09-03,COOP Milano,Groceries,-84.20
+2026-09-10,Esselunga,,-120.50
+2026-09-12,COOP Milano,Refund,10.00
+2026-09-15,Employer SpA,Salary,3000.00
+2026-09-20,Cinema Odeon,Tickets,-24.00
+2026-08-28,COOP Milano,Groceries,-60.00
+`
+
+func testPlan(t *testing.T) BudgetResult {
+	t.Helper()
+
+	plan, err := AllocateBudget(BudgetInput{
+		Income: NewMoney(2000),
+		Categories: []BudgetCategoryInput{
+			{Name: "Rent", Amount: NewMoney(1000), Fixed: true},
+			{Name: "Groceries", Percentage: 20},
+			{Name: "Fun", Percentage: 10},
+			{Name: "Savings", Percentage: 70},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return plan
+}
+
+func TestCompareBudget(t *testing.T) {
+	transactions, err := ParseStatementCSV(strings.NewReader(statementCSV), StatementInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rules, err := ParseCategoryRules(strings.NewReader("category,match\nRent,rent\nGroceries,/(?i)^(coop|esselunga)/\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result, err := CompareBudget(BudgetActualInput{Plan: testPlan(t), Transactions: transactions, Rules: rules})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Month != "2026-09" {
+		t.Errorf("Month = %s, want 2026-09 from the latest transaction", result.Month)
+	}
+
+	expected := []BudgetVariance{
+		{Category: "Rent", Planned: NewMoney(1000), Actual: NewMoney(1000), Variance: NewMoney(0), Transactions: 1},
+		{Category: "Groceries", Planned: NewMoney(200), Actual: NewMoney(194.70), Variance: NewMoney(5.30), VariancePercentage: 2.65, Transactions: 3},
+		{Category: "Fun", Planned: NewMoney(100), Variance: NewMoney(100), VariancePercentage: 100},
+		{Category: "Savings", Planned: NewMoney(700), Variance: NewMoney(700), VariancePercentage: 100},
+	}
+	if len(result.Categories) != len(expected) {
+		t.Fatalf("Categories = %+v, want %+v", result.Categories, expected)
+	}
+	for i, want := range expected {
+		got := result.Categories[i]
+		if got.Category != want.Category || got.Planned != want.Planned || got.Actual != want.Actual ||
+			got.Variance != want.Variance || got.Transactions != want.Transactions ||
+			!approximatelyEqual(got.VariancePercentage, want.VariancePercentage, 0.001) {
+			t.Errorf("Categories[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+
+	// The cinema ticket matches no rule, the salary is money received
+	if result.Uncategorized != NewMoney(24) || len(result.UncategorizedTransactions) != 1 {
+		t.Errorf("Uncategorized = %v (%d transactions), want 24.00 in 1", result.Uncategorized, len(result.UncategorizedTransactions))
+	}
+	if result.Received != NewMoney(3000) {
+		t.Errorf("Received = %v, want 3000.00", result.Received)
+	}
+	if result.Actual != NewMoney(1218.70) || result.Variance != NewMoney(781.30) {
+		t.Errorf("Actual = %v, Variance = %v, want 1218.70 and 781.30", result.Actual, result.Variance)
+	}
+
+	august, err := CompareBudget(BudgetActualInput{Plan: testPlan(t), Transactions: transactions, Rules: rules,
+		Month: time.Date(2026, time.August, 1, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if august.Categories[1].Actual != NewMoney(60) {
+		t.Errorf("August groceries = %v, want 60.00", august.Categories[1].Actual)
+	}
+}
+
+func TestCompareBudgetOverspent(t *testing.T) {
+	transactions := []Transaction{{Date: time.Date(2026, time.May, 4, 0, 0, 0, 0, time.UTC), Payee: "Cinema", Amount: NewMoney(-150)}}
+	rules := []CategoryRule{{Category: "fun", Keyword: "CINEMA"}}
+
+	result, err := CompareBudget(BudgetActualInput{Plan: testPlan(t), Transactions: transactions, Rules: rules})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	fun := result.Categories[2]
+	if fun.Variance != NewMoney(-50) || !approximatelyEqual(fun.VariancePercentage, -50, 0.001) {
+		t.Errorf("Fun variance = %v (%v%%), want -50.00 (-50%%)", fun.Variance, fun.VariancePercentage)
+	}
+}
+
+func TestCompareBudgetValidation(t *testing.T) {
+	transactions := []Transaction{{Date: time.Now(), Payee: "Cinema", Amount: NewMoney(-10)}}
+
+	invalid := map[string]BudgetActualInput{
+		"No transactions":  {Plan: testPlan(t)},
+		"Unknown category": {Plan: testPlan(t), Transactions: transactions, Rules: []CategoryRule{{Category: "Travel", Keyword: "train"}}},
+		"Empty rule":       {Plan: testPlan(t), Transactions: transactions, Rules: []CategoryRule{{Category: "Fun"}}},
+	}
+	for name, input := range invalid {
+		t.Run(name, func(t *testing.T) {
+			var validationErr *ValidationError
+			if _, err := CompareBudget(input); !errors.As(err, &validationErr) {
+				t.Errorf("Expected a ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseCategoryRule(t *testing.T) {
+	rule, err := ParseCategoryRule("Groceries=/^COOP/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !rule.Matches(Transaction{Payee: "COOP Milano"}) || rule.Matches(Transaction{Payee: "Milano COOP"}) {
+		t.Errorf("Rule %v should match payees starting with COOP", rule.Payee)
+	}
+
+	rule, err = ParseCategoryRule("Fun = netflix")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !rule.Matches(Transaction{Payee: "PayPal", Memo: "NETFLIX.COM subscription"}) {
+		t.Error("Keyword rules should match the memo case-insensitively")
+	}
+
+	for _, value := range []string{"Groceries", "=coop", "Groceries=", "Groceries=/[/"} {
+		if _, err := ParseCategoryRule(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package finance
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// LoadBudgetCategories reads budget categories from a CSV file with name,share[,group] rows
+func LoadBudgetCategories(path string) ([]BudgetCategoryInput, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseBudgetCategories(file)
+}
+
+// ParseBudgetCategories reads budget categories in CSV format. The share is a percentage,
+// or a fixed amount when it starts with €. The optional third column is the 50/30/20
+// group of the category. A header row is allowed, and lines starting
+// with # are comments.
+func ParseBudgetCategories(r io.Reader) ([]BudgetCategoryInput, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	categories := []BudgetCategoryInput{}
+	for i, record := range records {
+		if len(record) != 2 && len(record) != 3 {
+			return nil, fmt.Errorf("line %d: expected name,share[,group], got %d fields", i+1, len(record))
+		}
+		category := BudgetCategoryInput{Name: strings.TrimSpace(record[0])}
+		if err := parseBudgetShare(record[1], &category); err != nil {
+			if i == 0 {
+				continue // Header row
+			}
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if len(record) == 3 {
+			if category.Group, err = parseBudgetGroup(record[2]); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+		categories = append(categories, category)
+	}
+
+	return categories, nil
+}
+
+// ParseBudgetCategory parses a name=share[:group] category definition, where the
+// share is a percentage, or a fixed amount when it starts with €
+func ParseBudgetCategory(value string) (BudgetCategoryInput, error) {
+	name, share, found := strings.Cut(value, "=")
+	if !found || strings.TrimSpace(name) == "" {
+		return BudgetCategoryInput{}, fmt.Errorf("invalid category %q, expected name=percentage[:group]", value)
+	}
+	share, group, _ := strings.Cut(share, ":")
+
+	category := BudgetCategoryInput{Name: strings.TrimSpace(name)}
+	if err := parseBudgetShare(share, &category); err != nil {
+		return BudgetCategoryInput{}, fmt.Errorf("%w for category %s", err, category.Name)
+	}
+
+	budgetGroup, err := parseBudgetGroup(group)
+	if err != nil {
+		return BudgetCategoryInput{}, err
+	}
+	category.Group = budgetGroup
+
+	return category, nil
+}
+
+// ParseFixedBudgetCategory parses a name=amount[:group] definition of a category
+// with a fixed amount
+func ParseFixedBudgetCategory(value string) (BudgetCategoryInput, error) {
+	name, amount, found := strings.Cut(value, "=")
+	if !found || strings.TrimSpace(name) == "" {
+		return BudgetCategoryInput{}, fmt.Errorf("invalid fixed category %q, expected name=amount[:group]", value)
+	}
+	return ParseBudgetCategory(name + "=€" + strings.TrimPrefix(strings.TrimSpace(amount), "€"))
+}
+
+// parseBudgetShare reads a percentage such as 12.5 or 12.5%, or a fixed amount such as €900
+func parseBudgetShare(value string, category *BudgetCategoryInput) error {
+	value = strings.TrimSpace(value)
+	if amountValue, fixed := strings.CutPrefix(value, "€"); fixed {
+		amount, err := ParseMoney(amountValue)
+		if err != nil {
+			return err
+		}
+		category.Amount, category.Fixed = amount, true
+		return nil
+	}
+
+	percentage, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return fmt.Errorf("invalid percentage %q", value)
+	}
+	category.Percentage = percentage
+	return nil
+}
+
+// parseBudgetGroup parses a needs, wants or savings group name; empty means no group
+func parseBudgetGroup(value string) (BudgetGroup, error) {
+	group := BudgetGroup(strings.ToLower(strings.TrimSpace(value)))
+	switch group {
+	case "", GroupNeeds, GroupWants, GroupSavings:
+		return group, nil
+	default:
+		return "", fmt.Errorf("unknown budget group %q, expected needs, wants or savings", value)
+	}
+}
@@ -0,0 +1,112 @@
+package finance
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseBudgetCategories(t *testing.T) {
+	file := "name,percentage\n# Monthly plan\nRent,35\nChildcare, 12.5%\nPets,2\n"
+
+	categories, err := ParseBudgetCategories(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []BudgetCategoryInput{
+		{Name: "Rent", Percentage: 35},
+		{Name: "Childcare", Percentage: 12.5},
+		{Name: "Pets", Percentage: 2},
+	}
+	if len(categories) != len(expected) {
+		t.Fatalf("Categories = %+v, want %+v", categories, expected)
+	}
+	for i := range expected {
+		if categories[i] != expected[i] {
+			t.Errorf("Categories[%d] = %+v, want %+v", i, categories[i], expected[i])
+		}
+	}
+
+	if _, err := ParseBudgetCategories(strings.NewReader("Rent,35\nPets,abc\n")); err == nil {
+		t.Error("Expected an error for an invalid percentage")
+	}
+	if _, err := ParseBudgetCategories(strings.NewReader("Rent,35,needs,extra\n")); err == nil {
+		t.Error("Expected an error for a row with four fields")
+	}
+	if _, err := ParseBudgetCategories(strings.NewReader("Rent,35,extra\n")); err == nil {
+		t.Error("Expected an error for an unknown group")
+	}
+}
+
+func TestParseBudgetCategoriesWithGroups(t *testing.T) {
+	file := "name,share,group\nRent,35,needs\nTravel,10, Wants\nPension,5,savings\nGifts,2\nMortgage,€820.00,needs\n"
+
+	categories, err := ParseBudgetCategories(strings.NewReader(file))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []BudgetCategoryInput{
+		{Name: "Rent", Percentage: 35, Group: GroupNeeds},
+		{Name: "Travel", Percentage: 10, Group: GroupWants},
+		{Name: "Pension", Percentage: 5, Group: GroupSavings},
+		{Name: "Gifts", Percentage: 2},
+		{Name: "Mortgage", Amount: NewMoney(820), Fixed: true, Group: GroupNeeds},
+	}
+	if len(categories) != len(expected) {
+		t.Fatalf("Categories = %+v, want %+v", categories, expected)
+	}
+	for i := range expected {
+		if categories[i] != expected[i] {
+			t.Errorf("Categories[%d] = %+v, want %+v", i, categories[i], expected[i])
+		}
+	}
+}
+
+func TestParseBudgetCategory(t *testing.T) {
+	category, err := ParseBudgetCategory("Pets=3.5")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if category.Name != "Pets" || category.Percentage != 3.5 {
+		t.Errorf("Category = %+v, want Pets at 3.5%%", category)
+	}
+
+	category, err = ParseBudgetCategory("Pension=10:Savings")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if category.Name != "Pension" || category.Percentage != 10 || category.Group != GroupSavings {
+		t.Errorf("Category = %+v, want Pension at 10%% in savings", category)
+	}
+
+	category, err = ParseBudgetCategory("Rent=€950.50:needs")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if category != (BudgetCategoryInput{Name: "Rent", Amount: NewMoney(950.50), Fixed: true, Group: GroupNeeds}) {
+		t.Errorf("Category = %+v, want Rent fixed at 950.50 in needs", category)
+	}
+
+	for _, value := range []string{"Pets", "=5", "Pets=five", "Pets=5:fun", "Rent=€abc"} {
+		if _, err := ParseBudgetCategory(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
+
+func TestParseFixedBudgetCategory(t *testing.T) {
+	category, err := ParseFixedBudgetCategory("Car Loan=250:savings")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if category != (BudgetCategoryInput{Name: "Car Loan", Amount: NewMoney(250), Fixed: true, Group: GroupSavings}) {
+		t.Errorf("Category = %+v, want Car Loan fixed at 250.00 in savings", category)
+	}
+
+	for _, value := range []string{"Rent", "Rent=", "Rent=10%"} {
+		if _, err := ParseFixedBudgetCategory(value); err == nil {
+			t.Errorf("Expected an error for %q", value)
+		}
+	}
+}
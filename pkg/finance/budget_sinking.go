@@ -0,0 +1,245 @@
+package finance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSinkingMonths is the length of the sinking fund projection in months
+const DefaultSinkingMonths = 12
+
+// ExpenseFrequency is how often an irregular expense is due
+type ExpenseFrequency string
+
+const (
+	Annual     ExpenseFrequency = "annual"
+	Semiannual ExpenseFrequency = "semiannual"
+	Quarterly  ExpenseFrequency = "quarterly"
+)
+
+// paymentsPerYear maps each frequency to its number of payments in a year
+var paymentsPerYear = map[ExpenseFrequency]int{Annual: 1, Semiannual: 2, Quarterly: 4}
+
+// SinkingFundInput is an irregular expense that is saved for month by month
+type SinkingFundInput struct {
+	Name      string
+	Amount    Money            // Amount of each payment
+	Frequency ExpenseFrequency // Default: Annual
+	DueMonth  time.Month       // Month of a payment; the others follow at the frequency
+	Balance   Money            // Already saved at the start of the projection
+	Group     BudgetGroup      // Group of the set-aside budget category (default: GroupSavings)
+}
+
+// SinkingFundsInput represents the input parameters for planning sinking funds
+type SinkingFundsInput struct {
+	Funds  []SinkingFundInput
+	Start  time.Time // First month of the projection
+	Months int       // Length of the projection (default: DefaultSinkingMonths)
+}
+
+// SinkingFund is the plan of a sinking fund
+type SinkingFund struct {
+	Name            string           `json:"name"`
+	Amount          Money            `json:"amount"`
+	Frequency       ExpenseFrequency `json:"frequency"`
+	DueMonths       []time.Month     `json:"due_months"`
+	MonthlySetAside Money            `json:"monthly_set_aside"`  // Spreads the yearly cost evenly
+	CatchUpSetAside Money            `json:"catch_up_set_aside"` // Covers the next payment in time from the current balance
+	Shortfall       Money            `json:"shortfall"`          // Total missing on due dates with the monthly set-aside
+	FirstShortfall  string           `json:"first_shortfall"`    // YYYY-MM of the first due date with a shortfall, empty without
+}
+
+// SinkingFundMonth is a month of the projected balance of a sinking fund
+type SinkingFundMonth struct {
+	Fund      string `json:"fund"`
+	Month     string `json:"month"` // YYYY-MM
+	Deposit   Money  `json:"deposit"`
+	Payment   Money  `json:"payment"`
+	Balance   Money  `json:"balance"`   // After the deposit and the payment
+	Shortfall Money  `json:"shortfall"` // Part of the payment the fund could not cover
+}
+
+// SinkingFundsResult represents the output of sinking fund planning
+type SinkingFundsResult struct {
+	Funds           []SinkingFund      `json:"funds"`
+	MonthlySetAside Money              `json:"monthly_set_aside"`
+	Shortfall       Money              `json:"shortfall"`
+	Schedule        []SinkingFundMonth `json:"schedule"` // Month by month for each fund in turn
+}
+
+// Validate checks the funds and that the projection has a start month
+func (input SinkingFundsInput) Validate() error {
+	if input.Start.IsZero() {
+		return invalidInput("Start", "sinking funds need the first month of the projection")
+	}
+	if input.Months < 0 {
+		return invalidInput("Months", "the projection length must not be negative")
+	}
+
+	seen := map[string]bool{}
+	for _, fund := range input.Funds {
+		name := strings.TrimSpace(fund.Name)
+		if name == "" {
+			return invalidInput("Funds", "sinking funds need a name")
+		}
+		if seen[strings.ToLower(name)] {
+			return invalidInput("Funds", "duplicate sinking fund: %s", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		if !fund.Amount.IsPositive() {
+			return invalidInput("Amount", "the amount of %s must be positive", name)
+		}
+		if fund.Balance.IsNegative() {
+			return invalidInput("Balance", "the balance of %s must not be negative", name)
+		}
+		if fund.DueMonth < time.January || fund.DueMonth > time.December {
+			return invalidInput("DueMonth", "the due month of %s must be between 1 and 12", name)
+		}
+		if _, ok := paymentsPerYear[fund.Frequency]; !ok && fund.Frequency != "" {
+			return invalidInput("Frequency", "unknown frequency for %s: %s (expected annual, semiannual or quarterly)", name, fund.Frequency)
+		}
+		if _, err := parseBudgetGroup(string(fund.Group)); err != nil {
+			return invalidInput("Group", "unknown budget group for %s: %s (expected needs, wants or savings)", name, fund.Group)
+		}
+	}
+	return nil
+}
+
+// PlanSinkingFunds computes the monthly set-aside of each fund and projects its
+// balance month by month. A payment that the balance cannot cover is a shortfall,
+// which leaves the fund empty.
+func PlanSinkingFunds(input SinkingFundsInput) (SinkingFundsResult, error) {
+	if err := input.Validate(); err != nil {
+		return SinkingFundsResult{}, err
+	}
+
+	months := input.Months
+	if months == 0 {
+		months = DefaultSinkingMonths
+	}
+	start := time.Date(input.Start.Year(), input.Start.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	result := SinkingFundsResult{Funds: []SinkingFund{}, Schedule: []SinkingFundMonth{}}
+	for _, input := range input.Funds {
+		frequency := input.Frequency
+		if frequency == "" {
+			frequency = Annual
+		}
+		payments := paymentsPerYear[frequency]
+
+		fund := SinkingFund{
+			Name:      strings.TrimSpace(input.Name),
+			Amount:    input.Amount,
+			Frequency: frequency,
+			DueMonths: dueMonths(input.DueMonth, payments),
+		}
+		fund.MonthlySetAside = divideUpToCent(Money{units: input.Amount.units * int64(payments)}, 12)
+
+		// Months until the next payment, counting the start month and the due month
+		untilDue := 1
+		for !isDueMonth(start.AddDate(0, untilDue-1, 0).Month(), fund.DueMonths) {
+			untilDue++
+		}
+		fund.CatchUpSetAside = MaxMoney(divideUpToCent(MaxMoney(input.Amount.Sub(input.Balance), Money{}), int64(untilDue)), fund.MonthlySetAside)
+
+		balance := input.Balance
+		for i := range months {
+			month := start.AddDate(0, i, 0)
+			row := SinkingFundMonth{Fund: fund.Name, Month: month.Format(monthLayout), Deposit: fund.MonthlySetAside}
+			balance = balance.Add(fund.MonthlySetAside)
+
+			if isDueMonth(month.Month(), fund.DueMonths) {
+				row.Payment = input.Amount
+				if balance.Cmp(input.Amount) < 0 {
+					row.Shortfall = input.Amount.Sub(balance)
+					fund.Shortfall = fund.Shortfall.Add(row.Shortfall)
+					if fund.FirstShortfall == "" {
+						fund.FirstShortfall = row.Month
+					}
+				}
+				balance = MaxMoney(balance.Sub(input.Amount), Money{})
+			}
+
+			row.Balance = balance
+			result.Schedule = append(result.Schedule, row)
+		}
+
+		result.Funds = append(result.Funds, fund)
+		result.MonthlySetAside = result.MonthlySetAside.Add(fund.MonthlySetAside)
+		result.Shortfall = result.Shortfall.Add(fund.Shortfall)
+	}
+
+	return result, nil
+}
+
+// dueMonths returns the months of the payments in a year, starting from the due month
+func dueMonths(due time.Month, payments int) []time.Month {
+	months := make([]time.Month, payments)
+	for i := range months {
+		months[i] = time.Month((int(due)-1+i*12/payments)%12 + 1)
+	}
+	return months
+}
+
+func isDueMonth(month time.Month, due []time.Month) bool {
+	for _, d := range due {
+		if d == month {
+			return true
+		}
+	}
+	return false
+}
+
+// divideUpToCent divides an amount, rounding up to the cent so that the parts never fall short
+func divideUpToCent(m Money, divisor int64) Money {
+	const centUnits = moneyScale / 100
+	cents := (m.units + divisor*centUnits - 1) / (divisor * centUnits)
+	return Money{units: cents * centUnits}
+}
+
+// ParseSinkingFund parses a name=amount:frequency:due-month[:balance] definition. The
+// due month is a number or an English month name.
+func ParseSinkingFund(value string) (SinkingFundInput, error) {
+	name, definition, found := strings.Cut(value, "=")
+	fields := strings.Split(definition, ":")
+	if !found || strings.TrimSpace(name) == "" || len(fields) < 3 || len(fields) > 4 {
+		return SinkingFundInput{}, fmt.Errorf("invalid sinking fund %q, expected name=amount:frequency:due-month[:balance]", value)
+	}
+
+	fund := SinkingFundInput{Name: strings.TrimSpace(name), Frequency: ExpenseFrequency(strings.ToLower(strings.TrimSpace(fields[1])))}
+
+	amount, err := ParseMoney(fields[0])
+	if err != nil {
+		return SinkingFundInput{}, fmt.Errorf("sinking fund %s: %w", fund.Name, err)
+	}
+	fund.Amount = amount
+
+	if fund.DueMonth, err = parseMonth(fields[2]); err != nil {
+		return SinkingFundInput{}, fmt.Errorf("sinking fund %s: %w", fund.Name, err)
+	}
+
+	if len(fields) == 4 {
+		if fund.Balance, err = ParseMoney(fields[3]); err != nil {
+			return SinkingFundInput{}, fmt.Errorf("sinking fund %s: %w", fund.Name, err)
+		}
+	}
+	return fund, nil
+}
+
+// parseMonth reads a month number from 1 to 12 or an English month name or abbreviation
+func parseMonth(value string) (time.Month, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if number, err := strconv.Atoi(value); err == nil && number >= 1 && number <= 12 {
+		return time.Month(number), nil
+	}
+	for month := time.January; month <= time.December; month++ {
+		name := strings.ToLower(month.String())
+		if value == name || (len(value) >= 3 && strings.HasPrefix(name, value)) {
+			return month, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid month %q, expected 1-12 or a month name", value)
+}
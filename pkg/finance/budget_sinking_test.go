@@ -0,0 +1,204 @@
+package finance
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestPlanSinkingFunds(t *testing.T) {
+	start := time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name              string
+		fund              SinkingFundInput
+		expectedSetAside  float64
+		expectedCatchUp   float64
+		expectedShortfall float64
+		expectedFirst     string
+		expectedDue       []time.Month
+	}{
+		{
+			name:              "Annual expense due in a year",
+			fund:              SinkingFundInput{Name: "Holidays", Amount: NewMoney(1200), DueMonth: time.September},
+			expectedSetAside:  100,
+			expectedCatchUp:   100,
+			expectedShortfall: 0,
+			expectedDue:       []time.Month{time.September},
+		},
+		{
+			name:              "Annual expense due soon",
+			fund:              SinkingFundInput{Name: "Car Insurance", Amount: NewMoney(900), Frequency: Annual, DueMonth: time.March},
+			expectedSetAside:  75,
+			expectedCatchUp:   150,
+			expectedShortfall: 450,
+			expectedFirst:     "2027-03",
+			expectedDue:       []time.Month{time.March},
+		},
+		{
+			name:              "Semiannual expense with a balance",
+			fund:              SinkingFundInput{Name: "Property Tax", Amount: NewMoney(600), Frequency: Semiannual, DueMonth: time.June, Balance: NewMoney(200)},
+			expectedSetAside:  100,
+			expectedCatchUp:   133.34,
+			expectedShortfall: 100,
+			expectedFirst:     "2026-12",
+			expectedDue:       []time.Month{time.June, time.December},
+		},
+		{
+			name:              "Quarterly expense covered by the balance",
+			fund:              SinkingFundInput{Name: "Water", Amount: NewMoney(100), Frequency: Quarterly, DueMonth: time.November, Balance: NewMoney(100)},
+			expectedSetAside:  33.34,
+			expectedCatchUp:   33.34,
+			expectedShortfall: 0,
+			expectedDue:       []time.Month{time.November, time.February, time.May, time.August},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := PlanSinkingFunds(SinkingFundsInput{Funds: []SinkingFundInput{tt.fund}, Start: start})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			fund := result.Funds[0]
+			if fund.MonthlySetAside != NewMoney(tt.expectedSetAside) {
+				t.Errorf("MonthlySetAside = %v, want %.2f", fund.MonthlySetAside, tt.expectedSetAside)
+			}
+			if fund.CatchUpSetAside != NewMoney(tt.expectedCatchUp) {
+				t.Errorf("CatchUpSetAside = %v, want %.2f", fund.CatchUpSetAside, tt.expectedCatchUp)
+			}
+			if fund.Shortfall != NewMoney(tt.expectedShortfall) || fund.FirstShortfall != tt.expectedFirst {
+				t.Errorf("Shortfall = %v from %q, want %.2f from %q", fund.Shortfall, fund.FirstShortfall, tt.expectedShortfall, tt.expectedFirst)
+			}
+			if !slices.Equal(fund.DueMonths, tt.expectedDue) {
+				t.Errorf("DueMonths = %v, want %v", fund.DueMonths, tt.expectedDue)
+			}
+
+			if len(result.Schedule) != DefaultSinkingMonths {
+				t.Fatalf("Schedule has %d months, want %d", len(result.Schedule), DefaultSinkingMonths)
+			}
+			if result.Schedule[0].Month != "2026-10" || result.Schedule[11].Month != "2027-09" {
+				t.Errorf("Schedule runs from %s to %s, want 2026-10 to 2027-09", result.Schedule[0].Month, result.Schedule[11].Month)
+			}
+		})
+	}
+}
+
+func TestSinkingFundSchedule(t *testing.T) {
+	result, err := PlanSinkingFunds(SinkingFundsInput{
+		Funds:  []SinkingFundInput{{Name: "Car Insurance", Amount: NewMoney(300), DueMonth: time.December}},
+		Start:  time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC),
+		Months: 4,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []SinkingFundMonth{
+		{Fund: "Car Insurance", Month: "2026-10", Deposit: NewMoney(25), Balance: NewMoney(25)},
+		{Fund: "Car Insurance", Month: "2026-11", Deposit: NewMoney(25), Balance: NewMoney(50)},
+		{Fund: "Car Insurance", Month: "2026-12", Deposit: NewMoney(25), Payment: NewMoney(300), Shortfall: NewMoney(225)},
+		{Fund: "Car Insurance", Month: "2027-01", Deposit: NewMoney(25), Balance: NewMoney(25)},
+	}
+	if !slices.Equal(result.Schedule, expected) {
+		t.Errorf("Schedule = %+v, want %+v", result.Schedule, expected)
+	}
+	if result.MonthlySetAside != NewMoney(25) || result.Shortfall != NewMoney(225) {
+		t.Errorf("MonthlySetAside = %v, Shortfall = %v, want 25.00 and 225.00", result.MonthlySetAside, result.Shortfall)
+	}
+}
+
+func TestBudgetWithSinkingFunds(t *testing.T) {
+	month := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	funds := []SinkingFundInput{
+		{Name: "Car Insurance", Amount: NewMoney(900), DueMonth: time.March},
+		{Name: "Holidays", Amount: NewMoney(1800), DueMonth: time.August, Group: GroupWants},
+	}
+
+	result, err := AllocateBudget(BudgetInput{
+		Income:       NewMoney(3000),
+		Categories:   []BudgetCategoryInput{{Name: "Living", Percentage: 100, Group: GroupNeeds}},
+		SinkingFunds: funds,
+		Month:        month,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The set-asides are fixed categories, the percentages share the rest
+	checkCategory(t, result, "Car Insurance", 75, 2.5)
+	checkCategory(t, result, "Holidays", 150, 5)
+	checkCategory(t, result, "Living", 2775, 92.5)
+	if result.Categories[1].Group != GroupSavings || result.Categories[2].Group != GroupWants {
+		t.Errorf("Groups = %s and %s, want savings and wants", result.Categories[1].Group, result.Categories[2].Group)
+	}
+	if len(result.SinkingFunds) != 2 || len(result.SinkingSchedule) != 2*DefaultSinkingMonths {
+		t.Errorf("Got %d funds and %d scheduled months, want 2 and %d", len(result.SinkingFunds), len(result.SinkingSchedule), 2*DefaultSinkingMonths)
+	}
+
+	t.Run("Without sinking funds", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(3000)})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.SinkingFunds == nil || len(result.SinkingFunds) != 0 || len(result.SinkingSchedule) != 0 {
+			t.Errorf("SinkingFunds = %v, want an empty list", result.SinkingFunds)
+		}
+	})
+
+	invalid := map[string]BudgetInput{
+		"No start month":     {Income: NewMoney(3000), SinkingFunds: funds},
+		"Name of a category": {Income: NewMoney(3000), SinkingFunds: []SinkingFundInput{{Name: "housing", Amount: NewMoney(100), DueMonth: time.May}}, Month: month},
+		"Duplicate fund":     {Income: NewMoney(3000), SinkingFunds: append(funds, funds[0]), Month: month},
+		"Zero amount":        {Income: NewMoney(3000), SinkingFunds: []SinkingFundInput{{Name: "Gifts", DueMonth: time.December}}, Month: month},
+		"Negative balance":   {Income: NewMoney(3000), SinkingFunds: []SinkingFundInput{{Name: "Gifts", Amount: NewMoney(100), DueMonth: time.December, Balance: NewMoney(-1)}}, Month: month},
+		"Invalid due month":  {Income: NewMoney(3000), SinkingFunds: []SinkingFundInput{{Name: "Gifts", Amount: NewMoney(100), DueMonth: 13}}, Month: month},
+		"Unknown frequency":  {Income: NewMoney(3000), SinkingFunds: []SinkingFundInput{{Name: "Gifts", Amount: NewMoney(100), DueMonth: time.December, Frequency: "weekly"}}, Month: month},
+	}
+	for name, input := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := AllocateBudget(input)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected a ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseSinkingFund(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected SinkingFundInput
+		wantErr  bool
+	}{
+		{value: "Car Insurance=900:annual:3", expected: SinkingFundInput{Name: "Car Insurance", Amount: NewMoney(900), Frequency: Annual, DueMonth: time.March}},
+		{value: "Property Tax=600:Semiannual:june:200", expected: SinkingFundInput{Name: "Property Tax", Amount: NewMoney(600), Frequency: Semiannual, DueMonth: time.June, Balance: NewMoney(200)}},
+		{value: "Water=95.50:quarterly:Feb", expected: SinkingFundInput{Name: "Water", Amount: NewMoney(95.5), Frequency: Quarterly, DueMonth: time.February}},
+		{value: "Holidays=1800:annual", wantErr: true},
+		{value: "Holidays=abc:annual:8", wantErr: true},
+		{value: "Holidays=1800:annual:13", wantErr: true},
+		{value: "Holidays=1800:annual:ju", wantErr: true},
+		{value: "=1800:annual:8", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			fund, err := ParseSinkingFund(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", fund)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if fund != tt.expected {
+				t.Errorf("ParseSinkingFund(%q) = %+v, want %+v", tt.value, fund, tt.expected)
+			}
+		})
+	}
+}
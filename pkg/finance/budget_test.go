@@ -0,0 +1,551 @@
+package finance
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestAllocateBudget(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    BudgetInput
+		expected BudgetResult
+	}{
+		{
+			name: "Basic budget allocation with 100% total",
+			input: BudgetInput{
+				Income:     NewMoney(5000),
+				Categories: budgetCategories(30, 15, 10, 5, 10, 10, 15, 5),
+			},
+			expected: BudgetResult{
+				Income:          NewMoney(5000),
+				TotalPercentage: 100,
+				Total:           NewMoney(5000),
+				Status:          Balanced,
+				Warning:         "",
+			},
+		},
+		{
+			name: "Budget allocation with less than 100% total",
+			input: BudgetInput{
+				Income:     NewMoney(3000),
+				Categories: budgetCategories(25, 15, 10, 5, 5, 5, 10, 5),
+			},
+			expected: BudgetResult{
+				Income:          NewMoney(3000),
+				TotalPercentage: 80,
+				Total:           NewMoney(2400),
+				Residual:        NewMoney(600),
+				Status:          Unallocated,
+				Warning:         "Warning: 600.00 of income is not allocated to a category",
+			},
+		},
+		{
+			name: "Budget allocation with more than 100% total",
+			input: BudgetInput{
+				Income:     NewMoney(4000),
+				Categories: budgetCategories(35, 20, 15, 10, 10, 15, 10, 5),
+			},
+			expected: BudgetResult{
+				Income:          NewMoney(4000),
+				TotalPercentage: 120,
+				Total:           NewMoney(4800),
+				Residual:        NewMoney(-800),
+				Status:          OverAllocated,
+				Warning:         "Warning: the categories exceed the income by 800.00",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := AllocateBudget(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Check basic fields
+			if result.Income != tc.expected.Income {
+				t.Errorf("Income = %v, want %v", result.Income, tc.expected.Income)
+			}
+
+			if !approximatelyEqual(result.TotalPercentage, tc.expected.TotalPercentage, 0.001) {
+				t.Errorf("TotalPercentage = %v, want %v", result.TotalPercentage, tc.expected.TotalPercentage)
+			}
+
+			if !approximatelyEqual(result.Total.Float64(), tc.expected.Total.Float64(), 0.001) {
+				t.Errorf("Total = %v, want %v", result.Total, tc.expected.Total)
+			}
+
+			if result.Residual != tc.expected.Residual || result.Status != tc.expected.Status {
+				t.Errorf("Residual = %v (%s), want %v (%s)", result.Residual, result.Status, tc.expected.Residual, tc.expected.Status)
+			}
+
+			if result.Warning != tc.expected.Warning {
+				t.Errorf("Warning = %v, want %v", result.Warning, tc.expected.Warning)
+			}
+
+			// Check that we have the expected number of categories
+			if len(result.Categories) != 8 {
+				t.Errorf("Expected 8 budget categories, got %d", len(result.Categories))
+			}
+
+			// Check individual category calculations
+			for _, category := range tc.input.Categories {
+				checkCategory(t, result, category.Name, tc.input.Income.Float64()*category.Percentage/100, category.Percentage)
+			}
+		})
+	}
+}
+
+// budgetCategories builds the default categories with the given percentages
+func budgetCategories(percentages ...float64) []BudgetCategoryInput {
+	categories := DefaultBudgetCategories()
+	for i := range categories {
+		categories[i].Percentage = percentages[i]
+	}
+	return categories
+}
+
+// Helper function to check a specific budget category
+func checkCategory(t *testing.T, result BudgetResult, name string, expectedAmount, expectedPercentage float64) {
+	t.Helper()
+
+	for _, category := range result.Categories {
+		if category.Name == name {
+			if !approximatelyEqual(category.Amount.Float64(), expectedAmount, 0.001) {
+				t.Errorf("%s amount = %v, want %v", name, category.Amount, expectedAmount)
+			}
+
+			if !approximatelyEqual(category.Percentage, expectedPercentage, 0.001) {
+				t.Errorf("%s percentage = %v, want %v", name, category.Percentage, expectedPercentage)
+			}
+
+			return
+		}
+	}
+
+	t.Errorf("Category %s not found", name)
+}
+
+// TestBudgetEdgeCases tests edge cases for the budget calculator
+func TestBudgetEdgeCases(t *testing.T) {
+	// Test case with zero income
+	t.Run("Zero income", func(t *testing.T) {
+		input := BudgetInput{
+			Income:     NewMoney(0),
+			Categories: budgetCategories(30, 15, 10, 5, 10, 10, 15, 5),
+		}
+
+		result, err := AllocateBudget(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !result.Income.IsZero() {
+			t.Errorf("Income = %v, want 0", result.Income)
+		}
+
+		if !result.Total.IsZero() {
+			t.Errorf("Total = %v, want 0", result.Total)
+		}
+
+		// Check that all category amounts are zero
+		for _, category := range result.Categories {
+			if !category.Amount.IsZero() {
+				t.Errorf("%s amount = %v, want 0", category.Name, category.Amount)
+			}
+		}
+	})
+
+	// Test case with zero percentages
+	t.Run("Zero percentages", func(t *testing.T) {
+		input := BudgetInput{
+			Income:     NewMoney(3000),
+			Categories: budgetCategories(0, 0, 0, 0, 0, 0, 0, 0),
+		}
+
+		result, err := AllocateBudget(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.TotalPercentage != 0 {
+			t.Errorf("TotalPercentage = %v, want 0", result.TotalPercentage)
+		}
+
+		if !result.Total.IsZero() {
+			t.Errorf("Total = %v, want 0", result.Total)
+		}
+
+		if result.Warning == "" {
+			t.Errorf("Expected a warning message for zero percentages")
+		}
+
+		// Check that all category amounts are zero
+		for _, category := range result.Categories {
+			if !category.Amount.IsZero() {
+				t.Errorf("%s amount = %v, want 0", category.Name, category.Amount)
+			}
+
+			if category.Percentage != 0 {
+				t.Errorf("%s percentage = %v, want 0", category.Name, category.Percentage)
+			}
+		}
+	})
+
+	// Test case with negative income
+	t.Run("Negative income", func(t *testing.T) {
+		input := BudgetInput{
+			Income:     NewMoney(-2000),
+			Categories: budgetCategories(30, 15, 10, 5, 10, 10, 15, 5),
+		}
+
+		_, err := AllocateBudget(input)
+
+		// Negative income is rejected
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected a ValidationError, got %v", err)
+		}
+		if validationErr.Field != "Income" {
+			t.Errorf("Field = %v, want Income", validationErr.Field)
+		}
+	})
+
+	// Test case with a negative percentage
+	t.Run("Negative percentage", func(t *testing.T) {
+		input := BudgetInput{
+			Income: NewMoney(3000),
+			Categories: []BudgetCategoryInput{
+				{Name: "Housing", Percentage: 40},
+				{Name: "Food", Percentage: -10},
+			},
+		}
+
+		if _, err := AllocateBudget(input); err == nil {
+			t.Errorf("Expected an error for a negative percentage, got nil")
+		}
+	})
+}
+
+// TestBudgetCategoriesSumToIncome checks that rounding never loses or invents cents
+func TestBudgetCategoriesSumToIncome(t *testing.T) {
+	input := BudgetInput{
+		Income:     NewMoney(1234.57),
+		Categories: budgetCategories(33.3, 13.7, 7.1, 4.9, 11.1, 9.9, 13.3, 6.7),
+	}
+
+	result, err := AllocateBudget(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	sum := Money{}
+	for _, category := range result.Categories {
+		sum = sum.Add(category.Amount)
+	}
+	if sum != input.Income || result.Total != input.Income {
+		t.Errorf("Categories sum to %v (total %v), want %v", sum, result.Total, input.Income)
+	}
+}
+
+func TestCustomBudgetCategories(t *testing.T) {
+	t.Run("User-defined categories", func(t *testing.T) {
+		input := BudgetInput{
+			Income: NewMoney(4000),
+			Categories: []BudgetCategoryInput{
+				{Name: "Rent", Percentage: 40},
+				{Name: "Childcare", Percentage: 25},
+				{Name: "Pets", Percentage: 5},
+				{Name: "Savings", Percentage: 30},
+			},
+		}
+
+		result, err := AllocateBudget(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Categories) != 4 {
+			t.Fatalf("Expected 4 budget categories, got %d", len(result.Categories))
+		}
+		checkCategory(t, result, "Childcare", 1000, 25)
+		checkCategory(t, result, "Pets", 200, 5)
+		if result.Warning != "" {
+			t.Errorf("Warning = %q, want none", result.Warning)
+		}
+	})
+
+	t.Run("Default template", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(3000)})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Categories) != 8 {
+			t.Fatalf("Expected the 8 default categories, got %d", len(result.Categories))
+		}
+		checkCategory(t, result, "Housing", 900, 30)
+		checkCategory(t, result, "Discretionary", 300, 10)
+	})
+
+	invalid := map[string][]BudgetCategoryInput{
+		"Duplicate name": {{Name: "Pets", Percentage: 5}, {Name: "pets", Percentage: 5}},
+		"Empty name":     {{Name: " ", Percentage: 5}},
+	}
+	for name, categories := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := AllocateBudget(BudgetInput{Income: NewMoney(1000), Categories: categories})
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != "Categories" {
+				t.Errorf("Expected a Categories ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestBudgetStrategies(t *testing.T) {
+	t.Run("50-30-20", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(4000), Strategy: FiftyThirtyTwenty})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Needs weights 30/15/10/5/5 share 2000, savings 10/15 share 800, wants 400 on its own
+		checkCategory(t, result, "Housing", 923.08, 23.077)
+		checkCategory(t, result, "Debt Repayment", 320, 8)
+		checkCategory(t, result, "Savings", 480, 12)
+		checkCategory(t, result, "Discretionary", 1200, 30)
+		if result.Total != result.Income || !result.Residual.IsZero() {
+			t.Errorf("Total = %v, Residual = %v, want the whole income assigned", result.Total, result.Residual)
+		}
+
+		expected := []BudgetGroupTotal{
+			{Group: GroupNeeds, Amount: NewMoney(2000), Percentage: 50, Target: 50},
+			{Group: GroupWants, Amount: NewMoney(1200), Percentage: 30, Target: 30},
+			{Group: GroupSavings, Amount: NewMoney(800), Percentage: 20, Target: 20},
+		}
+		if len(result.Groups) != len(expected) {
+			t.Fatalf("Groups = %+v, want %+v", result.Groups, expected)
+		}
+		for i, group := range expected {
+			actual := result.Groups[i]
+			if actual.Group != group.Group || actual.Amount != group.Amount || actual.Target != group.Target ||
+				!approximatelyEqual(actual.Percentage, group.Percentage, 0.001) {
+				t.Errorf("Groups[%d] = %+v, want %+v", i, actual, group)
+			}
+		}
+	})
+
+	t.Run("50-30-20 with an empty group", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{
+			Income:   NewMoney(1000),
+			Strategy: FiftyThirtyTwenty,
+			Categories: []BudgetCategoryInput{
+				{Name: "Rent", Group: GroupNeeds},
+				{Name: "Groceries", Group: GroupNeeds},
+				{Name: "Pension", Percentage: 1, Group: GroupSavings},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Zero weights split the group evenly
+		checkCategory(t, result, "Rent", 250, 25)
+		checkCategory(t, result, "Pension", 200, 20)
+		if result.Residual != NewMoney(300) || result.Warning == "" {
+			t.Errorf("Residual = %v, Warning = %q, want 300.00 unassigned with a warning", result.Residual, result.Warning)
+		}
+	})
+
+	t.Run("Zero-based", func(t *testing.T) {
+		categories := []BudgetCategoryInput{{Name: "Rent", Percentage: 50}, {Name: "Food", Percentage: 30}}
+
+		// Both strategies flag the remainder, zero-based names the amount to assign
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(2000), Strategy: PercentageBudget, Categories: categories})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Residual != NewMoney(400) || result.Status != Unallocated {
+			t.Errorf("Residual = %v, Status = %s, want 400.00 unallocated", result.Residual, result.Status)
+		}
+
+		flagged := []struct {
+			name       string
+			categories []BudgetCategoryInput
+			residual   Money
+			status     BudgetStatus
+			warning    string
+		}{
+			{"Unassigned income", categories, NewMoney(400), Unallocated, "leaves 400.00 of income unassigned"},
+			{"Over-assigned", []BudgetCategoryInput{{Name: "Rent", Percentage: 50}, {Name: "Car", Fixed: true, Amount: NewMoney(1200)}, {Name: "Food", Percentage: 60}},
+				NewMoney(-1400), OverAllocated, "assigns 1400.00 more than the income"},
+		}
+		for _, tt := range flagged {
+			result, err := AllocateBudget(BudgetInput{Income: NewMoney(2000), Strategy: ZeroBased, Categories: tt.categories, PercentageBase: PercentOfIncome})
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", tt.name, err)
+			}
+			if len(result.Categories) != len(tt.categories) || result.Residual != tt.residual || result.Status != tt.status {
+				t.Errorf("%s: Residual = %v, Status = %s, want %v, %s with the allocation", tt.name, result.Residual, result.Status, tt.residual, tt.status)
+			}
+			if !strings.Contains(result.Warning, tt.warning) {
+				t.Errorf("%s: Warning = %q, want %q", tt.name, result.Warning, tt.warning)
+			}
+		}
+
+		result, err = AllocateBudget(BudgetInput{Income: NewMoney(2000), Strategy: ZeroBased})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !result.Residual.IsZero() || result.Status != Balanced || result.Warning != "" {
+			t.Errorf("Residual = %v, Warning = %q, want a fully assigned budget", result.Residual, result.Warning)
+		}
+	})
+
+	t.Run("Pay yourself first", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{
+			Income:      NewMoney(3000),
+			Strategy:    PayYourselfFirst,
+			SavingsRate: 25,
+			Categories: []BudgetCategoryInput{
+				{Name: "Pension", Percentage: 2, Group: GroupSavings},
+				{Name: "Emergency Fund", Percentage: 1, Group: GroupSavings},
+				{Name: "Rent", Percentage: 60, Group: GroupNeeds},
+				{Name: "Fun", Percentage: 40, Group: GroupWants},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// 750 is saved first, the rest is split 60/40
+		checkCategory(t, result, "Pension", 500, 16.667)
+		checkCategory(t, result, "Emergency Fund", 250, 8.333)
+		checkCategory(t, result, "Rent", 1350, 45)
+		checkCategory(t, result, "Fun", 900, 30)
+		if result.Total != result.Income || result.Warning != "" {
+			t.Errorf("Total = %v, Warning = %q, want the whole income assigned", result.Total, result.Warning)
+		}
+	})
+
+	t.Run("Pay yourself first with the default rate", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(1000), Strategy: PayYourselfFirst})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Groups[len(result.Groups)-1].Amount != NewMoney(200) {
+			t.Errorf("Groups = %+v, want 200.00 saved", result.Groups)
+		}
+	})
+
+	invalid := map[string]BudgetInput{
+		"Unknown strategy":       {Income: NewMoney(1000), Strategy: "envelope"},
+		"Savings rate above 100": {Income: NewMoney(1000), Strategy: PayYourselfFirst, SavingsRate: 120},
+		"Missing group":          {Income: NewMoney(1000), Strategy: FiftyThirtyTwenty, Categories: []BudgetCategoryInput{{Name: "Rent", Percentage: 50}}},
+		"No savings category": {Income: NewMoney(1000), Strategy: PayYourselfFirst,
+			Categories: []BudgetCategoryInput{{Name: "Rent", Percentage: 100, Group: GroupNeeds}}},
+	}
+	for name, input := range invalid {
+		t.Run(name, func(t *testing.T) {
+			var validationErr *ValidationError
+			if _, err := AllocateBudget(input); !errors.As(err, &validationErr) {
+				t.Errorf("Expected a ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestFixedBudgetCategories(t *testing.T) {
+	categories := []BudgetCategoryInput{
+		{Name: "Rent", Amount: NewMoney(1000), Fixed: true, Group: GroupNeeds},
+		{Name: "Car Loan", Amount: NewMoney(250), Fixed: true, Group: GroupSavings},
+		{Name: "Food", Percentage: 40, Group: GroupNeeds},
+		{Name: "Savings", Percentage: 40, Group: GroupSavings},
+		{Name: "Fun", Percentage: 20, Group: GroupWants},
+	}
+
+	t.Run("Percentages of the remainder", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(3250), Categories: categories})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// 2000 is left after the fixed amounts
+		checkCategory(t, result, "Rent", 1000, 30.769)
+		checkCategory(t, result, "Food", 800, 24.615)
+		checkCategory(t, result, "Fun", 400, 12.308)
+		if result.FixedTotal != NewMoney(1250) || !result.Residual.IsZero() || result.Status != Balanced {
+			t.Errorf("FixedTotal = %v, Residual = %v (%s), want 1250.00 fixed and a balanced budget", result.FixedTotal, result.Residual, result.Status)
+		}
+		if !approximatelyEqual(result.TotalPercentage, 100, 0.001) {
+			t.Errorf("TotalPercentage = %v, want 100", result.TotalPercentage)
+		}
+	})
+
+	t.Run("Percentages of gross income", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(3250), Categories: categories, PercentageBase: PercentOfIncome})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		checkCategory(t, result, "Food", 1300, 40)
+		if result.Residual != NewMoney(-1250) || result.Status != OverAllocated {
+			t.Errorf("Residual = %v (%s), want -1250.00 over-allocated", result.Residual, result.Status)
+		}
+	})
+
+	t.Run("Fixed amounts above the income", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(1000), Categories: categories})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		checkCategory(t, result, "Food", 0, 0)
+		if result.Residual != NewMoney(-250) || result.Warning != "Warning: the categories exceed the income by 250.00" {
+			t.Errorf("Residual = %v, Warning = %q, want 250.00 over-allocated", result.Residual, result.Warning)
+		}
+	})
+
+	t.Run("50-30-20", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(3000), Categories: categories, Strategy: FiftyThirtyTwenty})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Fixed amounts count toward the target of their group
+		checkCategory(t, result, "Food", 500, 16.667)
+		checkCategory(t, result, "Savings", 350, 11.667)
+		checkCategory(t, result, "Fun", 900, 30)
+		if !result.Residual.IsZero() {
+			t.Errorf("Residual = %v, want 0", result.Residual)
+		}
+	})
+
+	t.Run("Pay yourself first", func(t *testing.T) {
+		result, err := AllocateBudget(BudgetInput{Income: NewMoney(3250), Categories: categories, Strategy: PayYourselfFirst, SavingsRate: 10})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// 10% of the 2000 remainder is saved, Food and Fun split the other 1800 by 40/20
+		checkCategory(t, result, "Savings", 200, 6.154)
+		checkCategory(t, result, "Food", 1200, 36.923)
+		checkCategory(t, result, "Fun", 600, 18.462)
+	})
+
+	invalid := map[string]BudgetCategoryInput{
+		"Negative amount":           {Name: "Rent", Amount: NewMoney(-1), Fixed: true},
+		"Amount and percentage set": {Name: "Rent", Amount: NewMoney(900), Percentage: 30, Fixed: true},
+	}
+	for name, category := range invalid {
+		t.Run(name, func(t *testing.T) {
+			var validationErr *ValidationError
+			if _, err := AllocateBudget(BudgetInput{Income: NewMoney(1000), Categories: []BudgetCategoryInput{category}}); !errors.As(err, &validationErr) {
+				t.Errorf("Expected a ValidationError, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package finance
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultBaseCurrency is the currency used to derive cross rates
+const DefaultBaseCurrency = "EUR"
+
+// CurrencyInput represents the input parameters for currency conversion
+type CurrencyInput struct {
+	Amount Money
+	From   string
+	To     string
+	Base   string    // Currency for cross rates when no direct rate exists (default: DefaultBaseCurrency)
+	Date   time.Time // Day of the rate; zero uses the latest rate of the provider
+}
+
+// CurrencyResult represents the output of currency conversion
+type CurrencyResult struct {
+	Amount       Money     `json:"amount"`
+	From         string    `json:"from"`
+	To           string    `json:"to"`
+	Converted    Money     `json:"converted"` // Rounded to the minor unit of the target currency
+	ExchangeRate float64   `json:"exchange_rate"`
+	Path         []string  `json:"path"`           // Currencies the conversion went through, e.g. CHF, EUR, SEK
+	AsOf         time.Time `json:"as_of,omitzero"` // Date of the rate, zero when unknown
+	Note         string    `json:"note,omitempty"` // Set when the rate of an earlier day was used
+}
+
+// Validate checks that the amount is not negative and the currency codes are ISO 4217 codes
+func (input CurrencyInput) Validate() error {
+	if input.Amount.IsNegative() {
+		return invalidInput("Amount", "amount must not be negative")
+	}
+	if strings.TrimSpace(input.From) == "" {
+		return invalidInput("From", "source currency is required")
+	}
+	if strings.TrimSpace(input.To) == "" {
+		return invalidInput("To", "target currency is required")
+	}
+	if _, ok := LookupCurrency(input.From); !ok {
+		return invalidInput("From", "unsupported source currency: %s", strings.ToUpper(strings.TrimSpace(input.From)))
+	}
+	if _, ok := LookupCurrency(input.To); !ok {
+		return invalidInput("To", "unsupported target currency: %s", strings.ToUpper(strings.TrimSpace(input.To)))
+	}
+	if _, ok := LookupCurrency(input.Base); strings.TrimSpace(input.Base) != "" && !ok {
+		return invalidInput("Base", "unsupported base currency: %s", strings.ToUpper(strings.TrimSpace(input.Base)))
+	}
+	return nil
+}
+
+// ConvertCurrency converts an amount between two currencies with the rates of the
+// provider. A nil provider uses the built-in static rates. When the provider has no
+// rate for the pair, the rate is derived through the base currency. A conversion on
+// a Date needs a provider that implements DatedRateProvider.
+func ConvertCurrency(input CurrencyInput, provider RateProvider) (CurrencyResult, error) {
+	if err := input.Validate(); err != nil {
+		return CurrencyResult{}, err
+	}
+	if provider == nil {
+		provider = StaticRates()
+	}
+
+	from := strings.ToUpper(strings.TrimSpace(input.From))
+	to := strings.ToUpper(strings.TrimSpace(input.To))
+	target, _ := LookupCurrency(to)
+
+	result := CurrencyResult{
+		Amount: input.Amount,
+		From:   from,
+		To:     to,
+		Path:   []string{from},
+	}
+
+	if from == to {
+		result.Converted = input.Amount.Round(target.MinorUnits, DefaultRounding)
+		result.ExchangeRate = 1.0
+		return result, nil
+	}
+
+	base := strings.ToUpper(strings.TrimSpace(input.Base))
+	if base == "" {
+		base = DefaultBaseCurrency
+	}
+
+	lookup := provider.Rate
+	if !input.Date.IsZero() {
+		dated, ok := provider.(DatedRateProvider)
+		if !ok {
+			return CurrencyResult{}, invalidInput("Date", "the exchange rate source has no historical rates")
+		}
+		lookup = func(from, to string) (ExchangeRate, error) {
+			return dated.RateOn(from, to, input.Date)
+		}
+	}
+
+	rate, path, err := findRate(lookup, from, to, base)
+	if err != nil {
+		return CurrencyResult{}, err
+	}
+
+	result.Converted = input.Amount.Mul(rate.Rate).Round(target.MinorUnits, DefaultRounding)
+	result.ExchangeRate = rate.Rate
+	result.Path = path
+	result.AsOf = rate.AsOf
+	if !input.Date.IsZero() && rate.AsOf.Format(dateLayout) != input.Date.Format(dateLayout) {
+		result.Note = fmt.Sprintf("no rates published on %s, used the previous business day %s",
+			input.Date.Format(dateLayout), rate.AsOf.Format(dateLayout))
+	}
+	return result, nil
+}
+
+// findRate looks up the direct rate and falls back to a cross rate through the
+// base currency. It returns the currencies the rate goes through.
+func findRate(lookup func(from, to string) (ExchangeRate, error), from, to, base string) (ExchangeRate, []string, error) {
+	direct, err := lookup(from, to)
+	if err == nil {
+		return direct, []string{from, to}, nil
+	}
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || from == base || to == base {
+		return ExchangeRate{}, nil, err
+	}
+
+	toBase, err := lookup(from, base)
+	if err != nil {
+		return ExchangeRate{}, nil, err
+	}
+	fromBase, err := lookup(base, to)
+	if err != nil {
+		return ExchangeRate{}, nil, err
+	}
+
+	// A cross rate is only as recent as its older leg
+	asOf := toBase.AsOf
+	if fromBase.AsOf.Before(asOf) {
+		asOf = fromBase.AsOf
+	}
+
+	cross := ExchangeRate{From: from, To: to, Rate: toBase.Rate * fromBase.Rate, AsOf: asOf}
+	return cross, []string{from, base, to}, nil
+}
@@ -0,0 +1,210 @@
+package finance
+
+import (
+	"sort"
+	"strings"
+)
+
+// Currency describes an ISO 4217 currency
+type Currency struct {
+	Code       string `json:"code"`
+	Name       string `json:"name"`
+	Symbol     string `json:"symbol"`
+	MinorUnits int    `json:"minor_units"` // Decimal places of the minor unit, e.g. 2 for EUR and 0 for JPY
+}
+
+// isoCurrencies lists the active ISO 4217 codes. Precious metals, special drawing
+// rights and testing codes have no minor unit and are not included.
+var isoCurrencies = []Currency{
+	{Code: "AED", Name: "UAE Dirham", Symbol: "د.إ", MinorUnits: 2},
+	{Code: "AFN", Name: "Afghani", Symbol: "؋", MinorUnits: 2},
+	{Code: "ALL", Name: "Lek", Symbol: "L", MinorUnits: 2},
+	{Code: "AMD", Name: "Armenian Dram", Symbol: "֏", MinorUnits: 2},
+	{Code: "ANG", Name: "Netherlands Antillean Guilder", Symbol: "ƒ", MinorUnits: 2},
+	{Code: "AOA", Name: "Kwanza", Symbol: "Kz", MinorUnits: 2},
+	{Code: "ARS", Name: "Argentine Peso", Symbol: "$", MinorUnits: 2},
+	{Code: "AUD", Name: "Australian Dollar", Symbol: "A$", MinorUnits: 2},
+	{Code: "AWG", Name: "Aruban Florin", Symbol: "ƒ", MinorUnits: 2},
+	{Code: "AZN", Name: "Azerbaijan Manat", Symbol: "₼", MinorUnits: 2},
+	{Code: "BAM", Name: "Convertible Mark", Symbol: "KM", MinorUnits: 2},
+	{Code: "BBD", Name: "Barbados Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "BDT", Name: "Taka", Symbol: "৳", MinorUnits: 2},
+	{Code: "BGN", Name: "Bulgarian Lev", Symbol: "лв", MinorUnits: 2},
+	{Code: "BHD", Name: "Bahraini Dinar", Symbol: ".د.ب", MinorUnits: 3},
+	{Code: "BIF", Name: "Burundi Franc", Symbol: "FBu", MinorUnits: 0},
+	{Code: "BMD", Name: "Bermudian Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "BND", Name: "Brunei Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "BOB", Name: "Boliviano", Symbol: "Bs", MinorUnits: 2},
+	{Code: "BOV", Name: "Mvdol", Symbol: "BOV", MinorUnits: 2},
+	{Code: "BRL", Name: "Brazilian Real", Symbol: "R$", MinorUnits: 2},
+	{Code: "BSD", Name: "Bahamian Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "BTN", Name: "Ngultrum", Symbol: "Nu.", MinorUnits: 2},
+	{Code: "BWP", Name: "Pula", Symbol: "P", MinorUnits: 2},
+	{Code: "BYN", Name: "Belarusian Ruble", Symbol: "Br", MinorUnits: 2},
+	{Code: "BZD", Name: "Belize Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "CAD", Name: "Canadian Dollar", Symbol: "C$", MinorUnits: 2},
+	{Code: "CDF", Name: "Congolese Franc", Symbol: "FC", MinorUnits: 2},
+	{Code: "CHE", Name: "WIR Euro", Symbol: "CHE", MinorUnits: 2},
+	{Code: "CHF", Name: "Swiss Franc", Symbol: "CHF", MinorUnits: 2},
+	{Code: "CHW", Name: "WIR Franc", Symbol: "CHW", MinorUnits: 2},
+	{Code: "CLF", Name: "Unidad de Fomento", Symbol: "UF", MinorUnits: 4},
+	{Code: "CLP", Name: "Chilean Peso", Symbol: "$", MinorUnits: 0},
+	{Code: "CNY", Name: "Yuan Renminbi", Symbol: "¥", MinorUnits: 2},
+	{Code: "COP", Name: "Colombian Peso", Symbol: "$", MinorUnits: 2},
+	{Code: "COU", Name: "Unidad de Valor Real", Symbol: "COU", MinorUnits: 2},
+	{Code: "CRC", Name: "Costa Rican Colon", Symbol: "₡", MinorUnits: 2},
+	{Code: "CUP", Name: "Cuban Peso", Symbol: "$", MinorUnits: 2},
+	{Code: "CVE", Name: "Cabo Verde Escudo", Symbol: "$", MinorUnits: 2},
+	{Code: "CZK", Name: "Czech Koruna", Symbol: "Kč", MinorUnits: 2},
+	{Code: "DJF", Name: "Djibouti Franc", Symbol: "Fdj", MinorUnits: 0},
+	{Code: "DKK", Name: "Danish Krone", Symbol: "kr", MinorUnits: 2},
+	{Code: "DOP", Name: "Dominican Peso", Symbol: "$", MinorUnits: 2},
+	{Code: "DZD", Name: "Algerian Dinar", Symbol: "د.ج", MinorUnits: 2},
+	{Code: "EGP", Name: "Egyptian Pound", Symbol: "E£", MinorUnits: 2},
+	{Code: "ERN", Name: "Nakfa", Symbol: "Nfk", MinorUnits: 2},
+	{Code: "ETB", Name: "Ethiopian Birr", Symbol: "Br", MinorUnits: 2},
+	{Code: "EUR", Name: "Euro", Symbol: "€", MinorUnits: 2},
+	{Code: "FJD", Name: "Fiji Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "FKP", Name: "Falkland Islands Pound", Symbol: "£", MinorUnits: 2},
+	{Code: "GBP", Name: "Pound Sterling", Symbol: "£", MinorUnits: 2},
+	{Code: "GEL", Name: "Lari", Symbol: "₾", MinorUnits: 2},
+	{Code: "GHS", Name: "Ghana Cedi", Symbol: "₵", MinorUnits: 2},
+	{Code: "GIP", Name: "Gibraltar Pound", Symbol: "£", MinorUnits: 2},
+	{Code: "GMD", Name: "Dalasi", Symbol: "D", MinorUnits: 2},
+	{Code: "GNF", Name: "Guinean Franc", Symbol: "FG", MinorUnits: 0},
+	{Code: "GTQ", Name: "Quetzal", Symbol: "Q", MinorUnits: 2},
+	{Code: "GYD", Name: "Guyana Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "HKD", Name: "Hong Kong Dollar", Symbol: "HK$", MinorUnits: 2},
+	{Code: "HNL", Name: "Lempira", Symbol: "L", MinorUnits: 2},
+	{Code: "HTG", Name: "Gourde", Symbol: "G", MinorUnits: 2},
+	{Code: "HUF", Name: "Forint", Symbol: "Ft", MinorUnits: 2},
+	{Code: "IDR", Name: "Rupiah", Symbol: "Rp", MinorUnits: 2},
+	{Code: "ILS", Name: "New Israeli Sheqel", Symbol: "₪", MinorUnits: 2},
+	{Code: "INR", Name: "Indian Rupee", Symbol: "₹", MinorUnits: 2},
+	{Code: "IQD", Name: "Iraqi Dinar", Symbol: "ع.د", MinorUnits: 3},
+	{Code: "IRR", Name: "Iranian Rial", Symbol: "﷼", MinorUnits: 2},
+	{Code: "ISK", Name: "Iceland Krona", Symbol: "kr", MinorUnits: 0},
+	{Code: "JMD", Name: "Jamaican Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "JOD", Name: "Jordanian Dinar", Symbol: "د.ا", MinorUnits: 3},
+	{Code: "JPY", Name: "Yen", Symbol: "¥", MinorUnits: 0},
+	{Code: "KES", Name: "Kenyan Shilling", Symbol: "KSh", MinorUnits: 2},
+	{Code: "KGS", Name: "Som", Symbol: "с", MinorUnits: 2},
+	{Code: "KHR", Name: "Riel", Symbol: "៛", MinorUnits: 2},
+	{Code: "KMF", Name: "Comorian Franc", Symbol: "CF", MinorUnits: 0},
+	{Code: "KPW", Name: "North Korean Won", Symbol: "₩", MinorUnits: 2},
+	{Code: "KRW", Name: "Won", Symbol: "₩", MinorUnits: 0},
+	{Code: "KWD", Name: "Kuwaiti Dinar", Symbol: "د.ك", MinorUnits: 3},
+	{Code: "KYD", Name: "Cayman Islands Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "KZT", Name: "Tenge", Symbol: "₸", MinorUnits: 2},
+	{Code: "LAK", Name: "Lao Kip", Symbol: "₭", MinorUnits: 2},
+	{Code: "LBP", Name: "Lebanese Pound", Symbol: "ل.ل", MinorUnits: 2},
+	{Code: "LKR", Name: "Sri Lanka Rupee", Symbol: "Rs", MinorUnits: 2},
+	{Code: "LRD", Name: "Liberian Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "LSL", Name: "Loti", Symbol: "L", MinorUnits: 2},
+	{Code: "LYD", Name: "Libyan Dinar", Symbol: "ل.د", MinorUnits: 3},
+	{Code: "MAD", Name: "Moroccan Dirham", Symbol: "د.م.", MinorUnits: 2},
+	{Code: "MDL", Name: "Moldovan Leu", Symbol: "L", MinorUnits: 2},
+	{Code: "MGA", Name: "Malagasy Ariary", Symbol: "Ar", MinorUnits: 2},
+	{Code: "MKD", Name: "Denar", Symbol: "ден", MinorUnits: 2},
+	{Code: "MMK", Name: "Kyat", Symbol: "K", MinorUnits: 2},
+	{Code: "MNT", Name: "Tugrik", Symbol: "₮", MinorUnits: 2},
+	{Code: "MOP", Name: "Pataca", Symbol: "MOP$", MinorUnits: 2},
+	{Code: "MRU", Name: "Ouguiya", Symbol: "UM", MinorUnits: 2},
+	{Code: "MUR", Name: "Mauritius Rupee", Symbol: "₨", MinorUnits: 2},
+	{Code: "MVR", Name: "Rufiyaa", Symbol: "Rf", MinorUnits: 2},
+	{Code: "MWK", Name: "Malawi Kwacha", Symbol: "MK", MinorUnits: 2},
+	{Code: "MXN", Name: "Mexican Peso", Symbol: "$", MinorUnits: 2},
+	{Code: "MXV", Name: "Mexican Unidad de Inversion (UDI)", Symbol: "MXV", MinorUnits: 2},
+	{Code: "MYR", Name: "Malaysian Ringgit", Symbol: "RM", MinorUnits: 2},
+	{Code: "MZN", Name: "Mozambique Metical", Symbol: "MT", MinorUnits: 2},
+	{Code: "NAD", Name: "Namibia Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "NGN", Name: "Naira", Symbol: "₦", MinorUnits: 2},
+	{Code: "NIO", Name: "Cordoba Oro", Symbol: "C$", MinorUnits: 2},
+	{Code: "NOK", Name: "Norwegian Krone", Symbol: "kr", MinorUnits: 2},
+	{Code: "NPR", Name: "Nepalese Rupee", Symbol: "Rs", MinorUnits: 2},
+	{Code: "NZD", Name: "New Zealand Dollar", Symbol: "NZ$", MinorUnits: 2},
+	{Code: "OMR", Name: "Rial Omani", Symbol: "ر.ع.", MinorUnits: 3},
+	{Code: "PAB", Name: "Balboa", Symbol: "B/.", MinorUnits: 2},
+	{Code: "PEN", Name: "Sol", Symbol: "S/", MinorUnits: 2},
+	{Code: "PGK", Name: "Kina", Symbol: "K", MinorUnits: 2},
+	{Code: "PHP", Name: "Philippine Peso", Symbol: "₱", MinorUnits: 2},
+	{Code: "PKR", Name: "Pakistan Rupee", Symbol: "Rs", MinorUnits: 2},
+	{Code: "PLN", Name: "Zloty", Symbol: "zł", MinorUnits: 2},
+	{Code: "PYG", Name: "Guarani", Symbol: "₲", MinorUnits: 0},
+	{Code: "QAR", Name: "Qatari Rial", Symbol: "ر.ق", MinorUnits: 2},
+	{Code: "RON", Name: "Romanian Leu", Symbol: "lei", MinorUnits: 2},
+	{Code: "RSD", Name: "Serbian Dinar", Symbol: "дин.", MinorUnits: 2},
+	{Code: "RUB", Name: "Russian Ruble", Symbol: "₽", MinorUnits: 2},
+	{Code: "RWF", Name: "Rwanda Franc", Symbol: "FRw", MinorUnits: 0},
+	{Code: "SAR", Name: "Saudi Riyal", Symbol: "ر.س", MinorUnits: 2},
+	{Code: "SBD", Name: "Solomon Islands Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "SCR", Name: "Seychelles Rupee", Symbol: "₨", MinorUnits: 2},
+	{Code: "SDG", Name: "Sudanese Pound", Symbol: "ج.س.", MinorUnits: 2},
+	{Code: "SEK", Name: "Swedish Krona", Symbol: "kr", MinorUnits: 2},
+	{Code: "SGD", Name: "Singapore Dollar", Symbol: "S$", MinorUnits: 2},
+	{Code: "SHP", Name: "Saint Helena Pound", Symbol: "£", MinorUnits: 2},
+	{Code: "SLE", Name: "Leone", Symbol: "Le", MinorUnits: 2},
+	{Code: "SOS", Name: "Somali Shilling", Symbol: "Sh", MinorUnits: 2},
+	{Code: "SRD", Name: "Surinam Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "SSP", Name: "South Sudanese Pound", Symbol: "£", MinorUnits: 2},
+	{Code: "STN", Name: "Dobra", Symbol: "Db", MinorUnits: 2},
+	{Code: "SVC", Name: "El Salvador Colon", Symbol: "₡", MinorUnits: 2},
+	{Code: "SYP", Name: "Syrian Pound", Symbol: "£", MinorUnits: 2},
+	{Code: "SZL", Name: "Lilangeni", Symbol: "E", MinorUnits: 2},
+	{Code: "THB", Name: "Baht", Symbol: "฿", MinorUnits: 2},
+	{Code: "TJS", Name: "Somoni", Symbol: "SM", MinorUnits: 2},
+	{Code: "TMT", Name: "Turkmenistan New Manat", Symbol: "m", MinorUnits: 2},
+	{Code: "TND", Name: "Tunisian Dinar", Symbol: "د.ت", MinorUnits: 3},
+	{Code: "TOP", Name: "Pa'anga", Symbol: "T$", MinorUnits: 2},
+	{Code: "TRY", Name: "Turkish Lira", Symbol: "₺", MinorUnits: 2},
+	{Code: "TTD", Name: "Trinidad and Tobago Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "TWD", Name: "New Taiwan Dollar", Symbol: "NT$", MinorUnits: 2},
+	{Code: "TZS", Name: "Tanzanian Shilling", Symbol: "TSh", MinorUnits: 2},
+	{Code: "UAH", Name: "Hryvnia", Symbol: "₴", MinorUnits: 2},
+	{Code: "UGX", Name: "Uganda Shilling", Symbol: "USh", MinorUnits: 0},
+	{Code: "USD", Name: "US Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "USN", Name: "US Dollar (Next day)", Symbol: "USN", MinorUnits: 2},
+	{Code: "UYI", Name: "Uruguay Peso en Unidades Indexadas (UI)", Symbol: "UYI", MinorUnits: 0},
+	{Code: "UYU", Name: "Peso Uruguayo", Symbol: "$", MinorUnits: 2},
+	{Code: "UYW", Name: "Unidad Previsional", Symbol: "UYW", MinorUnits: 4},
+	{Code: "UZS", Name: "Uzbekistan Sum", Symbol: "so'm", MinorUnits: 2},
+	{Code: "VED", Name: "Bolívar Soberano", Symbol: "Bs.D", MinorUnits: 2},
+	{Code: "VES", Name: "Bolívar Soberano", Symbol: "Bs.S", MinorUnits: 2},
+	{Code: "VND", Name: "Dong", Symbol: "₫", MinorUnits: 0},
+	{Code: "VUV", Name: "Vatu", Symbol: "VT", MinorUnits: 0},
+	{Code: "WST", Name: "Tala", Symbol: "WS$", MinorUnits: 2},
+	{Code: "XAF", Name: "CFA Franc BEAC", Symbol: "FCFA", MinorUnits: 0},
+	{Code: "XCD", Name: "East Caribbean Dollar", Symbol: "$", MinorUnits: 2},
+	{Code: "XCG", Name: "Caribbean Guilder", Symbol: "Cg", MinorUnits: 2},
+	{Code: "XOF", Name: "CFA Franc BCEAO", Symbol: "CFA", MinorUnits: 0},
+	{Code: "XPF", Name: "CFP Franc", Symbol: "₣", MinorUnits: 0},
+	{Code: "YER", Name: "Yemeni Rial", Symbol: "﷼", MinorUnits: 2},
+	{Code: "ZAR", Name: "Rand", Symbol: "R", MinorUnits: 2},
+	{Code: "ZMW", Name: "Zambian Kwacha", Symbol: "ZK", MinorUnits: 2},
+	{Code: "ZWG", Name: "Zimbabwe Gold", Symbol: "ZiG", MinorUnits: 2},
+}
+
+// currencyIndex maps each ISO 4217 code to its metadata
+var currencyIndex = indexCurrencies(isoCurrencies)
+
+func indexCurrencies(list []Currency) map[string]Currency {
+	index := make(map[string]Currency, len(list))
+	for _, currency := range list {
+		index[currency.Code] = currency
+	}
+	return index
+}
+
+// LookupCurrency returns the metadata of an ISO 4217 currency code, case-insensitively
+func LookupCurrency(code string) (Currency, bool) {
+	currency, ok := currencyIndex[strings.ToUpper(strings.TrimSpace(code))]
+	return currency, ok
+}
+
+// Currencies returns all supported currencies sorted by code
+func Currencies() []Currency {
+	list := make([]Currency, len(isoCurrencies))
+	copy(list, isoCurrencies)
+	sort.Slice(list, func(i, j int) bool { return list[i].Code < list[j].Code })
+	return list
+}
@@ -0,0 +1,43 @@
+package finance
+
+import "testing"
+
+func TestLookupCurrency(t *testing.T) {
+	tests := []struct {
+		code       string
+		name       string
+		minorUnits int
+	}{
+		{code: "EUR", name: "Euro", minorUnits: 2},
+		{code: "jpy", name: "Yen", minorUnits: 0},
+		{code: "KWD", name: "Kuwaiti Dinar", minorUnits: 3},
+		{code: "CLF", name: "Unidad de Fomento", minorUnits: 4},
+	}
+
+	for _, tc := range tests {
+		currency, ok := LookupCurrency(tc.code)
+		if !ok {
+			t.Errorf("LookupCurrency(%q) not found", tc.code)
+			continue
+		}
+		if currency.Name != tc.name || currency.MinorUnits != tc.minorUnits {
+			t.Errorf("LookupCurrency(%q) = %+v, want %s with %d minor units", tc.code, currency, tc.name, tc.minorUnits)
+		}
+	}
+
+	if _, ok := LookupCurrency("XYZ"); ok {
+		t.Error("Expected XYZ to be unknown")
+	}
+}
+
+func TestCurrencies(t *testing.T) {
+	list := Currencies()
+	if len(list) < 150 {
+		t.Errorf("Currencies() returned %d codes, want the full ISO 4217 list", len(list))
+	}
+	for i := 1; i < len(list); i++ {
+		if list[i-1].Code >= list[i].Code {
+			t.Fatalf("Currencies() not sorted or duplicated at %s", list[i].Code)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package finance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultRatesURL is the ECB daily euro reference rates endpoint
+const DefaultRatesURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+// DefaultMaxAge is how long fetched rates are used before they count as stale
+const DefaultMaxAge = 24 * time.Hour
+
+// ratesCacheFile is the name of the cache file inside the cache directory
+const ratesCacheFile = "rates.json"
+
+// StalePolicy decides what happens when the cached rates are older than the max age
+type StalePolicy string
+
+const (
+	StaleRefresh StalePolicy = "refresh" // Fetch new rates; if that fails, use the stale cache with a warning
+	StaleWarn    StalePolicy = "warn"    // Use the stale cache with a warning without fetching
+	StaleRefuse  StalePolicy = "refuse"  // Fetch new rates; if that fails, return an error
+)
+
+// RateFetcher downloads exchange rates over HTTP and keeps the last response in an
+// on-disk cache, so that conversions keep working offline
+type RateFetcher struct {
+	URL      string        // ECB eurofxref XML or finz JSON rates endpoint (default: DefaultRatesURL)
+	CacheDir string        // Directory of the cache file (default: DefaultCacheDir)
+	MaxAge   time.Duration // Age after which cached rates are stale (default: DefaultMaxAge)
+	Policy   StalePolicy   // What to do with stale rates (default: StaleRefresh)
+	Client   *http.Client  // HTTP client (default: a client with a 10 second timeout)
+
+	now func() time.Time // Clock, replaced in tests
+}
+
+// FetchedRates is a rate table together with the time it was downloaded
+type FetchedRates struct {
+	*RateTable
+	FetchedAt time.Time
+	Warning   string // Set when stale rates are used or the cache could not be written
+}
+
+// ratesCache is the JSON layout of the cache file
+type ratesCache struct {
+	URL       string                        `json:"url"`
+	FetchedAt time.Time                     `json:"fetched_at"`
+	AsOf      time.Time                     `json:"as_of"`
+	Rates     map[string]map[string]float64 `json:"rates"`
+}
+
+// DefaultCacheDir returns the finz directory in the user configuration directory
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "finz"), nil
+}
+
+// Validate checks the staleness policy and the max age
+func (f *RateFetcher) Validate() error {
+	switch f.Policy {
+	case "", StaleRefresh, StaleWarn, StaleRefuse:
+	default:
+		return invalidInput("Policy", "unknown stale policy: %s (expected refresh, warn or refuse)", f.Policy)
+	}
+	if f.MaxAge < 0 {
+		return invalidInput("MaxAge", "max age must not be negative")
+	}
+	return nil
+}
+
+// Rates returns the cached rates while they are fresh. Otherwise it fetches new
+// rates and stores them in the cache, or applies the stale policy when that fails.
+func (f *RateFetcher) Rates(ctx context.Context) (*FetchedRates, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	cachePath, err := f.cachePath()
+	if err != nil {
+		return nil, err
+	}
+
+	cached, cacheErr := readRatesCache(cachePath)
+	if cacheErr == nil && cached.URL == f.url() {
+		age := f.currentTime().Sub(cached.FetchedAt)
+		if age <= f.maxAge() {
+			return cached.rates(""), nil
+		}
+
+		warning := fmt.Sprintf("exchange rates fetched %s ago are older than the max age of %s",
+			age.Round(time.Second), f.maxAge())
+		if f.Policy == StaleWarn {
+			return cached.rates(warning), nil
+		}
+
+		fetched, err := f.fetch(ctx, cachePath)
+		if err == nil {
+			return fetched, nil
+		}
+		if f.Policy == StaleRefuse {
+			return nil, fmt.Errorf("%s and refreshing them failed: %w", warning, err)
+		}
+		return cached.rates(fmt.Sprintf("%s, refreshing them failed: %v", warning, err)), nil
+	}
+
+	return f.fetch(ctx, cachePath)
+}
+
+// fetch downloads the rates and writes them to the cache. The rates are returned
+// even when the cache cannot be written, with a warning.
+func (f *RateFetcher) fetch(ctx context.Context, cachePath string) (*FetchedRates, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, f.url(), http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+
+	client := f.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching exchange rates: %s", response.Status)
+	}
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// JSON documents start with an object, anything else is read as ECB XML
+	var table *RateTable
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		table, err = ParseRatesJSON(bytes.NewReader(body))
+	} else {
+		table, err = ParseECBRates(bytes.NewReader(body))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading exchange rates from %s: %w", f.url(), err)
+	}
+
+	cache := ratesCache{URL: f.url(), FetchedAt: f.currentTime().UTC(), AsOf: table.AsOf, Rates: table.Rates}
+	if err := writeRatesCache(cachePath, cache); err != nil {
+		return cache.rates(fmt.Sprintf("exchange rates could not be cached: %v", err)), nil
+	}
+	return cache.rates(""), nil
+}
+
+func (f *RateFetcher) url() string {
+	if f.URL == "" {
+		return DefaultRatesURL
+	}
+	return f.URL
+}
+
+func (f *RateFetcher) maxAge() time.Duration {
+	if f.MaxAge == 0 {
+		return DefaultMaxAge
+	}
+	return f.MaxAge
+}
+
+func (f *RateFetcher) currentTime() time.Time {
+	if f.now == nil {
+		return time.Now()
+	}
+	return f.now()
+}
+
+func (f *RateFetcher) cachePath() (string, error) {
+	dir := f.CacheDir
+	if dir == "" {
+		var err error
+		if dir, err = DefaultCacheDir(); err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, ratesCacheFile), nil
+}
+
+// rates converts the cache contents to fetched rates
+func (c ratesCache) rates(warning string) *FetchedRates {
+	return &FetchedRates{
+		RateTable: &RateTable{Rates: c.Rates, AsOf: c.AsOf},
+		FetchedAt: c.FetchedAt,
+		Warning:   warning,
+	}
+}
+
+func readRatesCache(path string) (ratesCache, error) {
+	var cache ratesCache
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return cache, err
+	}
+	err = json.Unmarshal(data, &cache)
+	return cache, err
+}
+
+// writeRatesCache replaces the cache file atomically
+func writeRatesCache(path string, cache ratesCache) error {
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), ratesCacheFile+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return err
+	}
+	if err := temp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(temp.Name(), path)
+}
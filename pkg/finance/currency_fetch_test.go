@@ -0,0 +1,176 @@
+package finance
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rateServer serves the ECB test document and counts the requests
+func rateServer(t *testing.T, body string, requests *int) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		*requests++
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestRateFetcher(t *testing.T) {
+	ctx := context.Background()
+	start := time.Date(2024, 3, 15, 17, 0, 0, 0, time.UTC)
+
+	t.Run("Fetches once and then uses the cache", func(t *testing.T) {
+		requests := 0
+		server := rateServer(t, ecbDocument, &requests)
+		now := start
+		fetcher := &RateFetcher{URL: server.URL, CacheDir: t.TempDir(), MaxAge: time.Hour, now: func() time.Time { return now }}
+
+		for i := 0; i < 2; i++ {
+			rates, err := fetcher.Rates(ctx)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if rate, err := rates.Rate("EUR", "USD"); err != nil || rate.Rate != 1.0892 {
+				t.Errorf("EUR/USD = %v (%v), want 1.0892", rate.Rate, err)
+			}
+			if !rates.FetchedAt.Equal(start) || rates.Warning != "" {
+				t.Errorf("FetchedAt = %v, Warning = %q, want fresh rates fetched at %v", rates.FetchedAt, rates.Warning, start)
+			}
+			now = now.Add(30 * time.Minute)
+		}
+		if requests != 1 {
+			t.Errorf("Server received %d requests, want 1", requests)
+		}
+
+		// Past the max age the rates are fetched again
+		now = start.Add(2 * time.Hour)
+		rates, err := fetcher.Rates(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if requests != 2 || !rates.FetchedAt.Equal(now) {
+			t.Errorf("Requests = %d, FetchedAt = %v, want a refresh at %v", requests, rates.FetchedAt, now)
+		}
+	})
+
+	t.Run("JSON endpoint", func(t *testing.T) {
+		requests := 0
+		server := rateServer(t, `{"base": "USD", "date": "2024-03-15", "rates": {"EUR": 0.918}}`, &requests)
+		fetcher := &RateFetcher{URL: server.URL, CacheDir: t.TempDir()}
+
+		rates, err := fetcher.Rates(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		result, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(100), From: "USD", To: "EUR"}, rates)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Converted != NewMoney(91.8) {
+			t.Errorf("Converted = %v, want 91.80", result.Converted)
+		}
+	})
+
+	staleCache := func(t *testing.T, policy StalePolicy) (*RateFetcher, *httptest.Server) {
+		t.Helper()
+		requests := 0
+		server := rateServer(t, ecbDocument, &requests)
+		now := start
+		fetcher := &RateFetcher{URL: server.URL, CacheDir: t.TempDir(), Policy: policy, now: func() time.Time { return now }}
+		if _, err := fetcher.Rates(ctx); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		now = start.Add(48 * time.Hour)
+		return fetcher, server
+	}
+
+	t.Run("Offline with refresh policy", func(t *testing.T) {
+		fetcher, server := staleCache(t, StaleRefresh)
+		server.Close()
+
+		rates, err := fetcher.Rates(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(rates.Warning, "refreshing them failed") {
+			t.Errorf("Warning = %q, want a refresh failure warning", rates.Warning)
+		}
+	})
+
+	t.Run("Warn policy does not fetch", func(t *testing.T) {
+		fetcher, server := staleCache(t, StaleWarn)
+		server.Close()
+
+		rates, err := fetcher.Rates(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(rates.Warning, "older than the max age") || strings.Contains(rates.Warning, "refreshing") {
+			t.Errorf("Warning = %q, want a stale warning without a refresh", rates.Warning)
+		}
+		if !rates.FetchedAt.Equal(start) {
+			t.Errorf("FetchedAt = %v, want %v", rates.FetchedAt, start)
+		}
+	})
+
+	t.Run("Offline with refuse policy", func(t *testing.T) {
+		fetcher, server := staleCache(t, StaleRefuse)
+		server.Close()
+
+		if _, err := fetcher.Rates(ctx); err == nil {
+			t.Error("Expected an error for stale rates with the refuse policy")
+		}
+	})
+
+	t.Run("Cache cannot be written", func(t *testing.T) {
+		requests := 0
+		server := rateServer(t, ecbDocument, &requests)
+
+		// A regular file in place of the parent directory makes the cache unwritable
+		parent := filepath.Join(t.TempDir(), "file")
+		if err := os.WriteFile(parent, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		fetcher := &RateFetcher{URL: server.URL, CacheDir: filepath.Join(parent, "finz")}
+
+		rates, err := fetcher.Rates(ctx)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rate, err := rates.Rate("EUR", "USD"); err != nil || rate.Rate != 1.0892 {
+			t.Errorf("EUR/USD = %v (%v), want 1.0892", rate.Rate, err)
+		}
+		if !strings.Contains(rates.Warning, "could not be cached") {
+			t.Errorf("Warning = %q, want a cache warning", rates.Warning)
+		}
+	})
+
+	t.Run("No cache and no server", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		fetcher := &RateFetcher{URL: server.URL, CacheDir: t.TempDir()}
+		if _, err := fetcher.Rates(ctx); err == nil {
+			t.Error("Expected an error when the endpoint fails and there is no cache")
+		}
+	})
+
+	t.Run("Invalid policy", func(t *testing.T) {
+		fetcher := &RateFetcher{CacheDir: t.TempDir(), Policy: "sometimes"}
+
+		var validationErr *ValidationError
+		if _, err := fetcher.Rates(ctx); !errors.As(err, &validationErr) || validationErr.Field != "Policy" {
+			t.Errorf("Expected a Policy ValidationError, got %v", err)
+		}
+	})
+}
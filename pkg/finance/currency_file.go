@@ -0,0 +1,200 @@
+package finance
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Columns appended to each row of a converted CSV file
+var convertedColumns = []string{"exchange_rate", "rate_date", "converted_amount", "converted_currency"}
+
+// CurrencyFileInput represents the parameters for converting the rows of a CSV file
+type CurrencyFileInput struct {
+	To             string
+	Base           string // Currency for cross rates (default: DefaultBaseCurrency)
+	AmountColumn   string // Header of the amount column (default: amount)
+	CurrencyColumn string // Header of the currency column (default: currency)
+	DateColumn     string // Header of the optional date column (default: date)
+}
+
+// CurrencyTotal sums the rows of one source currency
+type CurrencyTotal struct {
+	Currency  string `json:"currency"`
+	Rows      int    `json:"rows"`
+	Amount    Money  `json:"amount"`
+	Converted Money  `json:"converted"`
+}
+
+// CurrencyFileResult summarizes the conversion of a CSV file
+type CurrencyFileResult struct {
+	To      string          `json:"to"`
+	Rows    int             `json:"rows"`
+	Total   Money           `json:"total"`
+	Totals  []CurrencyTotal `json:"totals"`  // One entry per source currency, sorted by code
+	Warning string          `json:"warning"` // Set when the dates of the file were ignored
+}
+
+// ConvertCurrencyCSV converts every row of a CSV file with a header row into the target
+// currency. The rows are written to w with the rate used and the converted amount
+// appended. When the file has a date column and the provider implements
+// DatedRateProvider, each row is converted at the rate of its date; other providers
+// convert every row at their latest rates and set a warning, unless the date column
+// is named in the input, which asks for the rates by date. Negative amounts, such as refunds, are
+// converted like positive ones.
+func ConvertCurrencyCSV(r io.Reader, w io.Writer, input CurrencyFileInput, provider RateProvider) (CurrencyFileResult, error) {
+	to := strings.ToUpper(strings.TrimSpace(input.To))
+	if _, ok := LookupCurrency(to); !ok {
+		return CurrencyFileResult{}, invalidInput("To", "unsupported target currency: %s", to)
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	writer := csv.NewWriter(w)
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return CurrencyFileResult{}, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return CurrencyFileResult{}, err
+	}
+
+	amountColumn := columnIndex(header, input.AmountColumn, "amount")
+	currencyColumn := columnIndex(header, input.CurrencyColumn, "currency")
+	dateColumn := columnIndex(header, input.DateColumn, "date")
+	if amountColumn < 0 || currencyColumn < 0 {
+		return CurrencyFileResult{}, fmt.Errorf("CSV header needs amount and currency columns, got %s", strings.Join(header, ","))
+	}
+	if input.DateColumn != "" && dateColumn < 0 {
+		return CurrencyFileResult{}, fmt.Errorf("CSV header has no %s column", input.DateColumn)
+	}
+	warning := ""
+	if _, dated := provider.(DatedRateProvider); dateColumn >= 0 && !dated {
+		if input.DateColumn != "" {
+			return CurrencyFileResult{}, invalidInput("Date", "converting by the %s column needs historical rates", header[dateColumn])
+		}
+		warning = fmt.Sprintf("the %s column was ignored: converting by date needs historical rates, the latest rates were used", header[dateColumn])
+		dateColumn = -1
+	}
+
+	if err := writer.Write(append(header, convertedColumns...)); err != nil {
+		return CurrencyFileResult{}, err
+	}
+
+	result := CurrencyFileResult{To: to, Totals: []CurrencyTotal{}, Warning: warning}
+	totals := map[string]*CurrencyTotal{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return CurrencyFileResult{}, err
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+
+		conversion, err := convertRecord(record, amountColumn, currencyColumn, dateColumn, to, input.Base, provider)
+		if err != nil {
+			return CurrencyFileResult{}, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		rateDate := ""
+		if !conversion.AsOf.IsZero() {
+			rateDate = conversion.AsOf.Format(dateLayout)
+		}
+		row := append(record, strconv.FormatFloat(conversion.ExchangeRate, 'f', -1, 64), rateDate,
+			conversion.Converted.String(), conversion.To)
+		if err := writer.Write(row); err != nil {
+			return CurrencyFileResult{}, err
+		}
+
+		total, ok := totals[conversion.From]
+		if !ok {
+			total = &CurrencyTotal{Currency: conversion.From}
+			totals[conversion.From] = total
+		}
+		total.Rows++
+		total.Amount = total.Amount.Add(conversion.Amount)
+		total.Converted = total.Converted.Add(conversion.Converted)
+		result.Rows++
+		result.Total = result.Total.Add(conversion.Converted)
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return CurrencyFileResult{}, err
+	}
+
+	for _, total := range totals {
+		result.Totals = append(result.Totals, *total)
+	}
+	sort.Slice(result.Totals, func(i, j int) bool { return result.Totals[i].Currency < result.Totals[j].Currency })
+
+	return result, nil
+}
+
+// convertRecord converts the amount of one CSV row. The result keeps the sign of the amount.
+func convertRecord(record []string, amountColumn, currencyColumn, dateColumn int, to, base string, provider RateProvider) (CurrencyResult, error) {
+	if amountColumn >= len(record) || currencyColumn >= len(record) {
+		return CurrencyResult{}, fmt.Errorf("missing amount or currency")
+	}
+
+	amount, err := ParseMoney(record[amountColumn])
+	if err != nil {
+		return CurrencyResult{}, err
+	}
+
+	input := CurrencyInput{Amount: amount, From: strings.TrimSpace(record[currencyColumn]), To: to, Base: base}
+	if amount.IsNegative() {
+		input.Amount = amount.Neg()
+	}
+	if dateColumn >= 0 && dateColumn < len(record) && strings.TrimSpace(record[dateColumn]) != "" {
+		date, err := time.Parse(dateLayout, strings.TrimSpace(record[dateColumn]))
+		if err != nil {
+			return CurrencyResult{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", record[dateColumn])
+		}
+		input.Date = date
+	}
+
+	result, err := ConvertCurrency(input, provider)
+	if err != nil {
+		return CurrencyResult{}, err
+	}
+	if amount.IsNegative() {
+		result.Amount = result.Amount.Neg()
+		result.Converted = result.Converted.Neg()
+	}
+	return result, nil
+}
+
+// columnIndex returns the position of the named column, or of the fallback name when
+// no name is given; -1 when the header has no such column
+func columnIndex(header []string, name, fallback string) int {
+	if name == "" {
+		name = fallback
+	}
+	for i, column := range header {
+		column = strings.TrimPrefix(column, "\ufeff") // Byte order mark of spreadsheet exports
+		if strings.EqualFold(strings.TrimSpace(column), name) {
+			return i
+		}
+	}
+	return -1
+}
+
+func isBlankRecord(record []string) bool {
+	for _, field := range record {
+		if strings.TrimSpace(field) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,138 @@
+package finance
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConvertCurrencyCSV(t *testing.T) {
+	table := &RateTable{Rates: map[string]map[string]float64{
+		"EUR": {"USD": 1.25, "GBP": 0.8, "JPY": 160},
+	}}
+
+	t.Run("Mixed currencies", func(t *testing.T) {
+		file := "description,Amount,Currency\n" +
+			"Hotel,125.00,USD\n" +
+			"Taxi,-12.50,usd\n" +
+			"Dinner,40,GBP\n" +
+			"\n" +
+			"Train,16000,JPY\n" +
+			"Coffee,3.20,EUR\n"
+
+		var out bytes.Buffer
+		result, err := ConvertCurrencyCSV(strings.NewReader(file), &out, CurrencyFileInput{To: "EUR"}, table)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if lines[0] != "description,Amount,Currency,exchange_rate,rate_date,converted_amount,converted_currency" {
+			t.Errorf("Header = %q", lines[0])
+		}
+		if lines[1] != "Hotel,125.00,USD,0.8,,100.00,EUR" {
+			t.Errorf("Row = %q, want Hotel,125.00,USD,0.8,,100.00,EUR", lines[1])
+		}
+		if lines[2] != "Taxi,-12.50,usd,0.8,,-10.00,EUR" {
+			t.Errorf("Row = %q, want the refund converted with its sign", lines[2])
+		}
+		if len(lines) != 6 {
+			t.Errorf("Expected 5 converted rows, got %d", len(lines)-1)
+		}
+
+		if result.Rows != 5 || result.Total != NewMoney(243.2) {
+			t.Errorf("Rows = %d, Total = %v, want 5 rows totalling 243.20", result.Rows, result.Total)
+		}
+
+		expected := []CurrencyTotal{
+			{Currency: "EUR", Rows: 1, Amount: NewMoney(3.2), Converted: NewMoney(3.2)},
+			{Currency: "GBP", Rows: 1, Amount: NewMoney(40), Converted: NewMoney(50)},
+			{Currency: "JPY", Rows: 1, Amount: NewMoney(16000), Converted: NewMoney(100)},
+			{Currency: "USD", Rows: 2, Amount: NewMoney(112.5), Converted: NewMoney(90)},
+		}
+		if len(result.Totals) != len(expected) {
+			t.Fatalf("Totals = %+v, want %+v", result.Totals, expected)
+		}
+		for i := range expected {
+			if result.Totals[i] != expected[i] {
+				t.Errorf("Totals[%d] = %+v, want %+v", i, result.Totals[i], expected[i])
+			}
+		}
+	})
+
+	t.Run("Rates by date", func(t *testing.T) {
+		history, err := ParseECBHistoryCSV(strings.NewReader(ecbHistoryCSV))
+		if err != nil {
+			t.Fatal(err)
+		}
+		file := "date,amount,currency\n2024-03-14,109.25,USD\n2024-03-16,108.92,USD\n"
+
+		var out bytes.Buffer
+		result, err := ConvertCurrencyCSV(strings.NewReader(file), &out, CurrencyFileInput{To: "EUR"}, history)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.Contains(out.String(), ",2024-03-15,100.00,EUR") {
+			t.Errorf("Output does not use the previous business day for a Saturday:\n%s", out.String())
+		}
+		if result.Total != NewMoney(200) || result.Warning != "" {
+			t.Errorf("Total = %v (warning %q), want 200.00 without a warning", result.Total, result.Warning)
+		}
+	})
+
+	t.Run("Dates without historical rates", func(t *testing.T) {
+		file := "date,amount,currency\n2024-03-14,125,USD\n"
+
+		// The latest rates apply unless the date column is asked for
+		var out bytes.Buffer
+		result, err := ConvertCurrencyCSV(strings.NewReader(file), &out, CurrencyFileInput{To: "EUR"}, table)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Total != NewMoney(100) || !strings.Contains(out.String(), "2024-03-14,125,USD,0.8,,100.00,EUR") {
+			t.Errorf("Total = %v, want 100.00 at the latest rate:\n%s", result.Total, out.String())
+		}
+		if !strings.Contains(result.Warning, "date column was ignored") {
+			t.Errorf("Warning = %q, want the ignored date column", result.Warning)
+		}
+
+		_, err = ConvertCurrencyCSV(strings.NewReader(file), &out, CurrencyFileInput{To: "EUR", DateColumn: "date"}, table)
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "Date" {
+			t.Errorf("Expected a Date ValidationError for a requested date column, got %v", err)
+		}
+	})
+
+	t.Run("Custom columns", func(t *testing.T) {
+		file := "Betrag,Waehrung\n10,GBP\n"
+
+		var out bytes.Buffer
+		input := CurrencyFileInput{To: "USD", AmountColumn: "Betrag", CurrencyColumn: "Waehrung"}
+		result, err := ConvertCurrencyCSV(strings.NewReader(file), &out, input, table)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Total != NewMoney(15.62) {
+			t.Errorf("Total = %v, want 15.625 through EUR rounded half-even to 15.62", result.Total)
+		}
+	})
+
+	invalid := []struct {
+		name string
+		file string
+	}{
+		{name: "Missing columns", file: "value,currency\n10,EUR\n"},
+		{name: "Invalid amount", file: "amount,currency\nten,EUR\n"},
+		{name: "Unknown currency", file: "amount,currency\n10,XYZ\n"},
+		{name: "Empty file", file: ""},
+	}
+	for _, tc := range invalid {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			if _, err := ConvertCurrencyCSV(strings.NewReader(tc.file), &out, CurrencyFileInput{To: "EUR"}, table); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
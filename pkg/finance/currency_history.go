@@ -0,0 +1,166 @@
+package finance
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxRateFallbackDays is how far back a historical lookup may fall back when no
+// rates were published on the requested date (weekends and bank holidays)
+const maxRateFallbackDays = 7
+
+// DatedRateProvider supplies the exchange rates published on a given date
+type DatedRateProvider interface {
+	RateOn(from, to string, date time.Time) (ExchangeRate, error)
+}
+
+// RateHistory is a store of daily euro reference rates. It answers with the rates
+// of the requested day, or of the closest earlier business day.
+type RateHistory struct {
+	days []ecbDay // Sorted by date
+}
+
+// Rate returns the most recent rate in the history
+func (h *RateHistory) Rate(from, to string) (ExchangeRate, error) {
+	if len(h.days) == 0 {
+		return ExchangeRate{}, fmt.Errorf("rate history is empty")
+	}
+	return h.RateOn(from, to, h.days[len(h.days)-1].Date)
+}
+
+// RateOn returns the rate published on the date, falling back to the previous
+// business day when there are no rates for that date
+func (h *RateHistory) RateOn(from, to string, date time.Time) (ExchangeRate, error) {
+	date = time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+
+	// Index of the first day after the requested date
+	next := sort.Search(len(h.days), func(i int) bool { return h.days[i].Date.After(date) })
+	if next == 0 {
+		return ExchangeRate{}, invalidInput("Date", "no exchange rates on or before %s", date.Format(dateLayout))
+	}
+
+	day := h.days[next-1]
+	if date.Sub(day.Date) > maxRateFallbackDays*24*time.Hour {
+		return ExchangeRate{}, invalidInput("Date", "no exchange rates within %d days before %s", maxRateFallbackDays, date.Format(dateLayout))
+	}
+
+	table := RateTable{Rates: map[string]map[string]float64{"EUR": day.Rates}, AsOf: day.Date}
+	return table.Rate(from, to)
+}
+
+// LoadRateHistory reads ECB historical reference rates from disk: the eurofxref-hist
+// CSV or XML file, or the zip archive the CSV is distributed in.
+func LoadRateHistory(path string) (*RateHistory, error) {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		return loadRateHistoryZip(path)
+	}
+
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ParseECBHistoryCSV(file)
+	case ".xml":
+		return ParseECBHistoryXML(file)
+	default:
+		return nil, fmt.Errorf("unknown rate history format %q (expected .csv, .xml or .zip)", filepath.Ext(path))
+	}
+}
+
+// loadRateHistoryZip reads the first CSV file of a zip archive
+func loadRateHistoryZip(path string) (*RateHistory, error) {
+	archive, err := zip.OpenReader(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	var entry *zip.File
+	for _, candidate := range archive.File {
+		if strings.EqualFold(filepath.Ext(candidate.Name), ".csv") {
+			entry = candidate
+			break
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("no CSV file in %s", path)
+	}
+
+	file, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseECBHistoryCSV(file)
+}
+
+// ParseECBHistoryXML reads the ECB eurofxref-hist XML format
+func ParseECBHistoryXML(r io.Reader) (*RateHistory, error) {
+	days, err := parseECBDays(r)
+	if err != nil {
+		return nil, err
+	}
+	return newRateHistory(days), nil
+}
+
+// ParseECBHistoryCSV reads the ECB eurofxref-hist CSV format: a Date column followed
+// by one column per currency. Missing quotes ("N/A" or empty) are skipped.
+func ParseECBHistoryCSV(r io.Reader) (*RateHistory, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) < 2 || !strings.EqualFold(strings.TrimSpace(records[0][0]), "Date") {
+		return nil, fmt.Errorf("expected a Date,currency... header and at least one row")
+	}
+
+	header := records[0]
+	days := make([]ecbDay, 0, len(records)-1)
+	for i, record := range records[1:] {
+		line := i + 2
+		date, err := time.Parse(dateLayout, strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q", line, record[0])
+		}
+
+		day := ecbDay{Date: date, Rates: map[string]float64{}}
+		for j := 1; j < len(record) && j < len(header); j++ {
+			currency := strings.ToUpper(strings.TrimSpace(header[j]))
+			value := strings.TrimSpace(record[j])
+			if currency == "" || value == "" || value == "N/A" {
+				continue
+			}
+			rate, err := strconv.ParseFloat(value, 64)
+			if err != nil || rate <= 0 {
+				return nil, fmt.Errorf("line %d: invalid %s rate %q", line, currency, value)
+			}
+			day.Rates[currency] = rate
+		}
+		days = append(days, day)
+	}
+
+	return newRateHistory(days), nil
+}
+
+// newRateHistory sorts the days by date
+func newRateHistory(days []ecbDay) *RateHistory {
+	sort.Slice(days, func(i, j int) bool { return days[i].Date.Before(days[j].Date) })
+	return &RateHistory{days: days}
+}
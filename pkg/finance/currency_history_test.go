@@ -0,0 +1,164 @@
+package finance
+
+import (
+	"archive/zip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ecbHistoryCSV follows the layout of eurofxref-hist.csv, including the trailing comma
+const ecbHistoryCSV = `Date,USD,JPY,CYP,CHF,
+2024-03-15,1.0892,161.86,N/A,0.9627,
+2024-03-14,1.0925,161.51,N/A,0.9632,
+2024-03-13,1.0951,161.92,N/A,0.9614,
+2024-03-28,1.0811,163.67,N/A,0.9766,
+`
+
+func mustParseDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	date, err := time.Parse(dateLayout, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return date
+}
+
+func TestRateHistory(t *testing.T) {
+	history, err := ParseECBHistoryCSV(strings.NewReader(ecbHistoryCSV))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		date     string
+		rate     float64
+		asOf     string
+		fallback bool
+	}{
+		{name: "Business day", date: "2024-03-14", rate: 1.0925, asOf: "2024-03-14"},
+		{name: "Saturday", date: "2024-03-16", rate: 1.0892, asOf: "2024-03-15", fallback: true},
+		{name: "Sunday", date: "2024-03-17", rate: 1.0892, asOf: "2024-03-15", fallback: true},
+		{name: "Easter Monday", date: "2024-04-01", rate: 1.0811, asOf: "2024-03-28", fallback: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			input := CurrencyInput{Amount: NewMoney(100), From: "EUR", To: "USD", Date: mustParseDate(t, tc.date)}
+			result, err := ConvertCurrency(input, history)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result.ExchangeRate != tc.rate {
+				t.Errorf("ExchangeRate = %v, want %v", result.ExchangeRate, tc.rate)
+			}
+			if result.AsOf.Format(dateLayout) != tc.asOf {
+				t.Errorf("AsOf = %v, want %s", result.AsOf, tc.asOf)
+			}
+			if (result.Note != "") != tc.fallback {
+				t.Errorf("Note = %q, want a note: %v", result.Note, tc.fallback)
+			}
+		})
+	}
+
+	t.Run("Cross rate on a date", func(t *testing.T) {
+		input := CurrencyInput{Amount: NewMoney(100), From: "CHF", To: "JPY", Date: mustParseDate(t, "2024-03-13")}
+		result, err := ConvertCurrency(input, history)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !approximatelyEqual(result.ExchangeRate, 161.92/0.9614, 0.000001) {
+			t.Errorf("ExchangeRate = %v, want %v", result.ExchangeRate, 161.92/0.9614)
+		}
+	})
+
+	t.Run("Latest rate without a date", func(t *testing.T) {
+		rate, err := history.Rate("EUR", "CHF")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if rate.Rate != 0.9766 {
+			t.Errorf("Rate = %v, want the latest rate 0.9766", rate.Rate)
+		}
+	})
+
+	t.Run("Missing quote", func(t *testing.T) {
+		if _, err := history.RateOn("EUR", "CYP", mustParseDate(t, "2024-03-14")); err == nil {
+			t.Error("Expected an error for a currency without quotes")
+		}
+	})
+
+	var validationErr *ValidationError
+	for _, date := range []string{"2024-03-01", "2024-05-01"} {
+		_, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(100), From: "EUR", To: "USD", Date: mustParseDate(t, date)}, history)
+		if !errors.As(err, &validationErr) || validationErr.Field != "Date" {
+			t.Errorf("Expected a Date ValidationError for %s, got %v", date, err)
+		}
+	}
+
+	_, err = ConvertCurrency(CurrencyInput{Amount: NewMoney(100), From: "EUR", To: "USD", Date: mustParseDate(t, "2024-03-15")}, StaticRates())
+	if !errors.As(err, &validationErr) || validationErr.Field != "Date" {
+		t.Errorf("Expected a Date ValidationError for a provider without history, got %v", err)
+	}
+}
+
+func TestParseECBHistoryXML(t *testing.T) {
+	document := `<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<Cube>
+		<Cube time="2024-03-15"><Cube currency="USD" rate="1.0892"/></Cube>
+		<Cube time="2024-03-14"><Cube currency="USD" rate="1.0925"/></Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+	history, err := ParseECBHistoryXML(strings.NewReader(document))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	rate, err := history.RateOn("USD", "EUR", mustParseDate(t, "2024-03-14"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !approximatelyEqual(rate.Rate, 1/1.0925, 0.000001) {
+		t.Errorf("Rate = %v, want %v", rate.Rate, 1/1.0925)
+	}
+}
+
+func TestLoadRateHistory(t *testing.T) {
+	dir := t.TempDir()
+
+	archivePath := filepath.Join(dir, "eurofxref-hist.zip")
+	file, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := zip.NewWriter(file)
+	entry, err := writer.Create("eurofxref-hist.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := entry.Write([]byte(ecbHistoryCSV)); err != nil {
+		t.Fatal(err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	history, err := LoadRateHistory(archivePath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rate, err := history.RateOn("EUR", "JPY", mustParseDate(t, "2024-03-15")); err != nil || rate.Rate != 161.86 {
+		t.Errorf("EUR/JPY = %v (%v), want 161.86", rate.Rate, err)
+	}
+
+	if _, err := ParseECBHistoryCSV(strings.NewReader("Day,USD\n2024-03-15,1.08\n")); err == nil {
+		t.Error("Expected an error for a file without a Date header")
+	}
+}
@@ -0,0 +1,256 @@
+package finance
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateLayout is the layout of the dates in rate files
+const dateLayout = "2006-01-02"
+
+// ExchangeRate is the price of one unit of From in units of To
+type ExchangeRate struct {
+	From string
+	To   string
+	Rate float64
+	AsOf time.Time // Date the rate refers to, zero when unknown
+}
+
+// RateProvider supplies exchange rates to ConvertCurrency
+type RateProvider interface {
+	Rate(from, to string) (ExchangeRate, error)
+}
+
+// RateTable is a RateProvider backed by a fixed set of rates. Inverse pairs are
+// derived from the direct rate when only one direction is listed.
+type RateTable struct {
+	Rates map[string]map[string]float64 // Rates[from][to]
+	AsOf  time.Time
+}
+
+// StaticRates returns the built-in rate table. The rates are indicative only and never updated.
+func StaticRates() *RateTable {
+	return &RateTable{Rates: map[string]map[string]float64{
+		"EUR": {"USD": 1.09, "GBP": 0.85, "JPY": 160.0},
+		"USD": {"EUR": 0.92, "GBP": 0.78, "JPY": 147.0},
+		"GBP": {"EUR": 1.18, "USD": 1.28, "JPY": 188.0},
+		"JPY": {"EUR": 0.00625, "USD": 0.0068, "GBP": 0.0053},
+	}}
+}
+
+// Rate returns the rate from one currency to another
+func (t *RateTable) Rate(from, to string) (ExchangeRate, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+
+	if rate, ok := t.Rates[from][to]; ok {
+		return ExchangeRate{From: from, To: to, Rate: rate, AsOf: t.AsOf}, nil
+	}
+	if rate, ok := t.Rates[to][from]; ok && rate != 0 {
+		return ExchangeRate{From: from, To: to, Rate: 1 / rate, AsOf: t.AsOf}, nil
+	}
+
+	if !t.has(from) {
+		return ExchangeRate{}, invalidInput("From", "unsupported source currency: %s", from)
+	}
+	return ExchangeRate{}, invalidInput("To", "unsupported target currency: %s", to)
+}
+
+// has reports whether the table quotes the currency in either direction
+func (t *RateTable) has(currency string) bool {
+	if _, ok := t.Rates[currency]; ok {
+		return true
+	}
+	for _, quotes := range t.Rates {
+		if _, ok := quotes[currency]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// set stores a rate, validating that it is positive
+func (t *RateTable) set(from, to string, rate float64) error {
+	if rate <= 0 {
+		return fmt.Errorf("rate %s/%s must be positive", from, to)
+	}
+	from, to = strings.ToUpper(strings.TrimSpace(from)), strings.ToUpper(strings.TrimSpace(to))
+	if t.Rates == nil {
+		t.Rates = map[string]map[string]float64{}
+	}
+	if t.Rates[from] == nil {
+		t.Rates[from] = map[string]float64{}
+	}
+	t.Rates[from][to] = rate
+	return nil
+}
+
+// LoadRatesFile reads a rate table from disk. The format follows the file extension:
+// .json and .csv for the finz rate formats, .xml for the ECB eurofxref format.
+func LoadRatesFile(path string) (*RateTable, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return ParseRatesJSON(file)
+	case ".csv":
+		return ParseRatesCSV(file)
+	case ".xml":
+		return ParseECBRates(file)
+	default:
+		return nil, fmt.Errorf("unknown rates file format %q (expected .json, .csv or .xml)", filepath.Ext(path))
+	}
+}
+
+// ParseRatesJSON reads rates quoted against a base currency:
+//
+//	{"base": "EUR", "date": "2024-03-15", "rates": {"USD": 1.0892, "GBP": 0.8555}}
+func ParseRatesJSON(r io.Reader) (*RateTable, error) {
+	var document struct {
+		Base  string             `json:"base"`
+		Date  string             `json:"date"`
+		Rates map[string]float64 `json:"rates"`
+	}
+	if err := json.NewDecoder(r).Decode(&document); err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(document.Base) == "" {
+		return nil, fmt.Errorf("rates file has no base currency")
+	}
+
+	table := &RateTable{}
+	if document.Date != "" {
+		asOf, err := time.Parse(dateLayout, document.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q", document.Date)
+		}
+		table.AsOf = asOf
+	}
+	for currency, rate := range document.Rates {
+		if err := table.set(document.Base, currency, rate); err != nil {
+			return nil, err
+		}
+	}
+
+	return table, nil
+}
+
+// ParseRatesCSV reads from,to,rate[,date] rows. A header row is allowed; the table
+// is dated with the most recent date in the file.
+func ParseRatesCSV(r io.Reader) (*RateTable, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	table := &RateTable{}
+	for i, record := range records {
+		if len(record) < 3 || len(record) > 4 {
+			return nil, fmt.Errorf("line %d: expected from,to,rate[,date]", i+1)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[2]), 64)
+		if err != nil {
+			if i == 0 {
+				continue // Header row
+			}
+			return nil, fmt.Errorf("line %d: invalid rate %q", i+1, record[2])
+		}
+		if err := table.set(record[0], record[1], rate); err != nil {
+			return nil, fmt.Errorf("line %d: %w", i+1, err)
+		}
+		if len(record) == 4 && strings.TrimSpace(record[3]) != "" {
+			asOf, err := time.Parse(dateLayout, strings.TrimSpace(record[3]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid date %q", i+1, record[3])
+			}
+			if asOf.After(table.AsOf) {
+				table.AsOf = asOf
+			}
+		}
+	}
+
+	return table, nil
+}
+
+// ecbDay holds the euro reference rates published for one day
+type ecbDay struct {
+	Date  time.Time
+	Rates map[string]float64
+}
+
+// ParseECBRates reads the ECB eurofxref XML format and returns the most recent day.
+// All rates are quoted against EUR.
+func ParseECBRates(r io.Reader) (*RateTable, error) {
+	days, err := parseECBDays(r)
+	if err != nil {
+		return nil, err
+	}
+
+	latest := days[0]
+	for _, day := range days[1:] {
+		if day.Date.After(latest.Date) {
+			latest = day
+		}
+	}
+
+	table := &RateTable{AsOf: latest.Date}
+	for currency, rate := range latest.Rates {
+		if err := table.set("EUR", currency, rate); err != nil {
+			return nil, err
+		}
+	}
+	return table, nil
+}
+
+// parseECBDays decodes every day of an ECB eurofxref document
+func parseECBDays(r io.Reader) ([]ecbDay, error) {
+	var envelope struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube>Cube"`
+	}
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, err
+	}
+	if len(envelope.Days) == 0 {
+		return nil, fmt.Errorf("no exchange rates found in ECB document")
+	}
+
+	days := make([]ecbDay, 0, len(envelope.Days))
+	for _, cube := range envelope.Days {
+		date, err := time.Parse(dateLayout, cube.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ECB date %q", cube.Time)
+		}
+		day := ecbDay{Date: date, Rates: map[string]float64{}}
+		for _, quote := range cube.Rates {
+			rate, err := strconv.ParseFloat(quote.Rate, 64)
+			if err != nil || rate <= 0 {
+				return nil, fmt.Errorf("invalid ECB rate %q for %s on %s", quote.Rate, quote.Currency, cube.Time)
+			}
+			day.Rates[strings.ToUpper(quote.Currency)] = rate
+		}
+		days = append(days, day)
+	}
+
+	return days, nil
+}
@@ -0,0 +1,152 @@
+package finance
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const ecbDocument = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time="2024-03-15">
+			<Cube currency="USD" rate="1.0892"/>
+			<Cube currency="JPY" rate="161.86"/>
+			<Cube currency="GBP" rate="0.85515"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestRateTable(t *testing.T) {
+	table := StaticRates()
+
+	rate, err := table.Rate("eur", "usd")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rate.From != "EUR" || rate.To != "USD" || rate.Rate != 1.09 {
+		t.Errorf("Rate = %+v, want EUR/USD 1.09", rate)
+	}
+
+	// Only one direction is listed, the other one is derived
+	inverse := &RateTable{Rates: map[string]map[string]float64{"EUR": {"CHF": 0.8}}}
+	rate, err = inverse.Rate("CHF", "EUR")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !approximatelyEqual(rate.Rate, 1.25, 0.000001) {
+		t.Errorf("CHF/EUR = %v, want 1.25", rate.Rate)
+	}
+
+	var validationErr *ValidationError
+	if _, err := inverse.Rate("XYZ", "EUR"); !errors.As(err, &validationErr) || validationErr.Field != "From" {
+		t.Errorf("Expected a From ValidationError, got %v", err)
+	}
+	if _, err := inverse.Rate("EUR", "XYZ"); !errors.As(err, &validationErr) || validationErr.Field != "To" {
+		t.Errorf("Expected a To ValidationError, got %v", err)
+	}
+}
+
+func TestParseECBRates(t *testing.T) {
+	table, err := ParseECBRates(strings.NewReader(ecbDocument))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !table.AsOf.Equal(time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("AsOf = %v, want 2024-03-15", table.AsOf)
+	}
+
+	result, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(100), From: "EUR", To: "USD"}, table)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Converted != NewMoney(108.92) {
+		t.Errorf("Converted = %v, want 108.92", result.Converted)
+	}
+	if !result.AsOf.Equal(table.AsOf) {
+		t.Errorf("AsOf = %v, want %v", result.AsOf, table.AsOf)
+	}
+
+	if _, err := ParseECBRates(strings.NewReader(`<Envelope><Cube></Cube></Envelope>`)); err == nil {
+		t.Error("Expected an error for a document without rates")
+	}
+}
+
+func TestParseRatesJSON(t *testing.T) {
+	table, err := ParseRatesJSON(strings.NewReader(`{"base": "usd", "date": "2024-01-02", "rates": {"EUR": 0.91, "CHF": 0.85}}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	rate, err := table.Rate("USD", "CHF")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rate.Rate != 0.85 || rate.AsOf.Format(dateLayout) != "2024-01-02" {
+		t.Errorf("Rate = %+v, want 0.85 as of 2024-01-02", rate)
+	}
+
+	invalid := []string{
+		`{"rates": {"EUR": 0.91}}`,
+		`{"base": "USD", "rates": {"EUR": -1}}`,
+		`{"base": "USD", "date": "02/01/2024", "rates": {"EUR": 0.91}}`,
+	}
+	for _, document := range invalid {
+		if _, err := ParseRatesJSON(strings.NewReader(document)); err == nil {
+			t.Errorf("Expected an error for %s", document)
+		}
+	}
+}
+
+func TestParseRatesCSV(t *testing.T) {
+	table, err := ParseRatesCSV(strings.NewReader("from,to,rate,date\nEUR,USD,1.08,2024-03-14\nGBP,USD,1.27,2024-03-15\n"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if table.AsOf.Format(dateLayout) != "2024-03-15" {
+		t.Errorf("AsOf = %v, want the most recent date 2024-03-15", table.AsOf)
+	}
+	if rate, err := table.Rate("GBP", "USD"); err != nil || rate.Rate != 1.27 {
+		t.Errorf("GBP/USD = %v (%v), want 1.27", rate.Rate, err)
+	}
+
+	if _, err := ParseRatesCSV(strings.NewReader("EUR,USD,1.08\nEUR,GBP,abc\n")); err == nil {
+		t.Error("Expected an error for an invalid rate")
+	}
+}
+
+func TestLoadRatesFile(t *testing.T) {
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "eurofxref.xml")
+	if err := os.WriteFile(path, []byte(ecbDocument), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	table, err := LoadRatesFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if rate, err := table.Rate("EUR", "JPY"); err != nil || rate.Rate != 161.86 {
+		t.Errorf("EUR/JPY = %v (%v), want 161.86", rate.Rate, err)
+	}
+
+	if _, err := LoadRatesFile(filepath.Join(dir, "rates.txt")); err == nil {
+		t.Error("Expected an error for a missing file")
+	}
+	unknown := filepath.Join(dir, "rates.txt")
+	if err := os.WriteFile(unknown, []byte("EUR,USD,1.08"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadRatesFile(unknown); err == nil {
+		t.Error("Expected an error for an unknown extension")
+	}
+}
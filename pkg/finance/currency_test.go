@@ -0,0 +1,312 @@
+package finance
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestConvertCurrency(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    CurrencyInput
+		expected CurrencyResult
+	}{
+		{
+			name: "EUR to USD conversion",
+			input: CurrencyInput{
+				Amount: NewMoney(100),
+				From:   "EUR",
+				To:     "USD",
+			},
+			expected: CurrencyResult{
+				Amount:       NewMoney(100),
+				From:         "EUR",
+				To:           "USD",
+				Converted:    NewMoney(109),
+				ExchangeRate: 1.09,
+			},
+		},
+		{
+			name: "USD to JPY conversion",
+			input: CurrencyInput{
+				Amount: NewMoney(50),
+				From:   "USD",
+				To:     "JPY",
+			},
+			expected: CurrencyResult{
+				Amount:       NewMoney(50),
+				From:         "USD",
+				To:           "JPY",
+				Converted:    NewMoney(7350),
+				ExchangeRate: 147.0,
+			},
+		},
+		{
+			name: "GBP to EUR conversion",
+			input: CurrencyInput{
+				Amount: NewMoney(75),
+				From:   "GBP",
+				To:     "EUR",
+			},
+			expected: CurrencyResult{
+				Amount:       NewMoney(75),
+				From:         "GBP",
+				To:           "EUR",
+				Converted:    NewMoney(88.5),
+				ExchangeRate: 1.18,
+			},
+		},
+		{
+			name: "JPY to GBP conversion",
+			input: CurrencyInput{
+				Amount: NewMoney(10000),
+				From:   "JPY",
+				To:     "GBP",
+			},
+			expected: CurrencyResult{
+				Amount:       NewMoney(10000),
+				From:         "JPY",
+				To:           "GBP",
+				Converted:    NewMoney(53),
+				ExchangeRate: 0.0053,
+			},
+		},
+		{
+			name: "Same currency conversion",
+			input: CurrencyInput{
+				Amount: NewMoney(200),
+				From:   "USD",
+				To:     "USD",
+			},
+			expected: CurrencyResult{
+				Amount:       NewMoney(200),
+				From:         "USD",
+				To:           "USD",
+				Converted:    NewMoney(200),
+				ExchangeRate: 1.0,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := ConvertCurrency(tc.input, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Check basic fields
+			if result.Amount != tc.expected.Amount {
+				t.Errorf("Amount = %v, want %v", result.Amount, tc.expected.Amount)
+			}
+			if result.From != tc.expected.From {
+				t.Errorf("From = %v, want %v", result.From, tc.expected.From)
+			}
+			if result.To != tc.expected.To {
+				t.Errorf("To = %v, want %v", result.To, tc.expected.To)
+			}
+
+			// For floating point values, use approximate comparison
+			const tolerance = 0.001 // 0.1% tolerance
+
+			if !approximatelyEqual(result.Converted.Float64(), tc.expected.Converted.Float64(), tolerance) {
+				t.Errorf("Converted = %v, want approximately %v", result.Converted, tc.expected.Converted)
+			}
+			if !approximatelyEqual(result.ExchangeRate, tc.expected.ExchangeRate, tolerance) {
+				t.Errorf("ExchangeRate = %v, want approximately %v", result.ExchangeRate, tc.expected.ExchangeRate)
+			}
+		})
+	}
+}
+
+// TestCurrencyEdgeCases tests edge cases for the currency converter
+func TestCurrencyEdgeCases(t *testing.T) {
+	// Test case with unsupported source currency
+	t.Run("Unsupported source currency", func(t *testing.T) {
+		input := CurrencyInput{
+			Amount: NewMoney(100),
+			From:   "XYZ", // Invalid currency
+			To:     "USD",
+		}
+
+		result, err := ConvertCurrency(input, nil)
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Expected a ValidationError for unsupported source currency, got %v", err)
+		} else if err.Error() != "unsupported source currency: XYZ" {
+			t.Errorf("Error = %v, want 'unsupported source currency: XYZ'", err)
+		}
+
+		// Converted amount and exchange rate should be zero
+		if !result.Converted.IsZero() {
+			t.Errorf("Converted = %v, want 0", result.Converted)
+		}
+		if result.ExchangeRate != 0 {
+			t.Errorf("ExchangeRate = %v, want 0", result.ExchangeRate)
+		}
+	})
+
+	// Test case with unsupported target currency
+	t.Run("Unsupported target currency", func(t *testing.T) {
+		input := CurrencyInput{
+			Amount: NewMoney(100),
+			From:   "USD",
+			To:     "XYZ", // Invalid currency
+		}
+
+		result, err := ConvertCurrency(input, nil)
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Errorf("Expected a ValidationError for unsupported target currency, got %v", err)
+		} else if err.Error() != "unsupported target currency: XYZ" {
+			t.Errorf("Error = %v, want 'unsupported target currency: XYZ'", err)
+		}
+
+		// Converted amount and exchange rate should be zero
+		if !result.Converted.IsZero() {
+			t.Errorf("Converted = %v, want 0", result.Converted)
+		}
+		if result.ExchangeRate != 0 {
+			t.Errorf("ExchangeRate = %v, want 0", result.ExchangeRate)
+		}
+	})
+
+	// Test case with zero amount
+	t.Run("Zero amount", func(t *testing.T) {
+		input := CurrencyInput{
+			Amount: NewMoney(0),
+			From:   "EUR",
+			To:     "USD",
+		}
+
+		result, err := ConvertCurrency(input, nil)
+
+		// No error should occur
+		if err != nil {
+			t.Errorf("Error = %v, want nil", err)
+		}
+
+		// Converted amount should be zero
+		if !result.Converted.IsZero() {
+			t.Errorf("Converted = %v, want 0", result.Converted)
+		}
+
+		// Exchange rate should be correct
+		if !approximatelyEqual(result.ExchangeRate, 1.09, 0.001) {
+			t.Errorf("ExchangeRate = %v, want 1.09", result.ExchangeRate)
+		}
+	})
+
+	// Test case with lowercase currency codes
+	t.Run("Lowercase currency codes", func(t *testing.T) {
+		input := CurrencyInput{
+			Amount: NewMoney(100),
+			From:   "eur",
+			To:     "usd",
+		}
+
+		result, err := ConvertCurrency(input, nil)
+
+		// No error should occur
+		if err != nil {
+			t.Errorf("Error = %v, want nil", err)
+		}
+
+		// Currency codes should be uppercase in the result
+		if result.From != "EUR" {
+			t.Errorf("From = %v, want EUR", result.From)
+		}
+		if result.To != "USD" {
+			t.Errorf("To = %v, want USD", result.To)
+		}
+
+		// Conversion should work correctly
+		if !approximatelyEqual(result.Converted.Float64(), 109, 0.001) {
+			t.Errorf("Converted = %v, want 109", result.Converted)
+		}
+	})
+
+	// Test case with negative amount
+	t.Run("Negative amount", func(t *testing.T) {
+		_, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(-10), From: "EUR", To: "USD"}, nil)
+
+		if err == nil {
+			t.Errorf("Expected an error for a negative amount, got nil")
+		}
+	})
+}
+
+func TestCrossRates(t *testing.T) {
+	table := &RateTable{Rates: map[string]map[string]float64{
+		"EUR": {"CHF": 0.96, "SEK": 11.2, "JPY": 160},
+		"USD": {"CHF": 0.88},
+	}}
+
+	t.Run("Through the base currency", func(t *testing.T) {
+		result, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(96), From: "CHF", To: "SEK"}, table)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(result.Path, ">") != "CHF>EUR>SEK" {
+			t.Errorf("Path = %v, want CHF, EUR, SEK", result.Path)
+		}
+		if result.Converted != NewMoney(1120) {
+			t.Errorf("Converted = %v, want 1120", result.Converted)
+		}
+	})
+
+	t.Run("Direct rate", func(t *testing.T) {
+		result, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(100), From: "USD", To: "CHF"}, table)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(result.Path) != 2 {
+			t.Errorf("Path = %v, want a direct conversion", result.Path)
+		}
+	})
+
+	t.Run("Custom base currency", func(t *testing.T) {
+		result, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(100), From: "USD", To: "EUR", Base: "CHF"}, table)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(result.Path, ">") != "USD>CHF>EUR" {
+			t.Errorf("Path = %v, want USD, CHF, EUR", result.Path)
+		}
+		if !approximatelyEqual(result.ExchangeRate, 0.88/0.96, 0.000001) {
+			t.Errorf("ExchangeRate = %v, want %v", result.ExchangeRate, 0.88/0.96)
+		}
+	})
+
+	t.Run("Rounded to the minor unit", func(t *testing.T) {
+		result, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(10.01), From: "EUR", To: "JPY"}, table)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if result.Converted != NewMoney(1602) {
+			t.Errorf("Converted = %v, want 1602 (no decimals for JPY)", result.Converted)
+		}
+	})
+
+	t.Run("Codes with surrounding spaces", func(t *testing.T) {
+		result, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(96), From: " chf", To: "sek ", Base: " eur "}, table)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if strings.Join(result.Path, ">") != "CHF>EUR>SEK" {
+			t.Errorf("Path = %v, want CHF, EUR, SEK", result.Path)
+		}
+	})
+
+	t.Run("No rate for the pair", func(t *testing.T) {
+		_, err := ConvertCurrency(CurrencyInput{Amount: NewMoney(100), From: "CHF", To: "NOK"}, table)
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "To" {
+			t.Errorf("Expected a To ValidationError, got %v", err)
+		}
+	})
+}
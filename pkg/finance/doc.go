@@ -0,0 +1,25 @@
+// Package finance implements the finz calculators: investments, loans, savings,
+// retirement, Monte Carlo simulation, historical backtesting, multi-asset portfolios
+// with rebalancing, currency conversion, budget allocation and bank statement import.
+//
+// Each calculator takes an input struct and returns a result struct and an error.
+// Invalid inputs are reported as *ValidationError, naming the offending field.
+// Amounts are fixed-point Money values; rates and percentages are float64 in percent.
+//
+//	result, err := finance.CalculateLoan(finance.LoanInput{
+//		Principal: finance.NewMoney(250000),
+//		Rate:      3.5,
+//		Years:     30,
+//	})
+//
+// # API stability
+//
+// The package follows semantic versioning. Within a major version, exported
+// identifiers are not removed or renamed, function signatures do not change and
+// the JSON field names of the result types stay the same. New fields and new
+// functions may be added in minor versions, so construct inputs with field names
+// rather than positional struct literals.
+package finance
+
+// Version is the semantic version of the finance API
+const Version = "1.0.0"
@@ -0,0 +1,26 @@
+package finance
+
+import "fmt"
+
+// ValidationError reports an input parameter outside its valid range
+type ValidationError struct {
+	Field   string // Name of the invalid input field
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// invalidInput returns a ValidationError for the given field
+func invalidInput(field, format string, args ...any) error {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
+// validatePercentage checks that a rate in percent is within [min, max]
+func validatePercentage(field string, value, min, max float64) error {
+	if value < min || value > max {
+		return invalidInput(field, "%s must be between %g%% and %g%%", field, min, max)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package finance
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidationError(t *testing.T) {
+	err := invalidInput("Years", "loan term must be at least one year")
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Expected a ValidationError, got %T", err)
+	}
+	if validationErr.Field != "Years" {
+		t.Errorf("Field = %v, want Years", validationErr.Field)
+	}
+	if err.Error() != "loan term must be at least one year" {
+		t.Errorf("Error = %q, want %q", err.Error(), "loan term must be at least one year")
+	}
+}
+
+func TestValidatePercentage(t *testing.T) {
+	if err := validatePercentage("TaxRate", 26, 0, 100); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if err := validatePercentage("TaxRate", 120, 0, 100); err == nil {
+		t.Error("Expected an error for a percentage above the maximum")
+	}
+	if err := validatePercentage("TaxRate", -1, 0, 100); err == nil {
+		t.Error("Expected an error for a percentage below the minimum")
+	}
+}
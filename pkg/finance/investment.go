@@ -0,0 +1,272 @@
+package finance
+
+import (
+	"math"
+)
+
+// ContributionFrequency is how often a recurring contribution is invested
+type ContributionFrequency string
+
+const (
+	ContributeMonthly   ContributionFrequency = "monthly"
+	ContributeQuarterly ContributionFrequency = "quarterly"
+	ContributeYearly    ContributionFrequency = "yearly"
+)
+
+// ItalianStampDuty is the yearly stamp duty (imposta di bollo) on investment holdings
+// in Italy, in percent of their value
+const ItalianStampDuty = 0.2
+
+// contributionMonths maps each frequency to the number of months between contributions
+var contributionMonths = map[ContributionFrequency]int{ContributeMonthly: 1, ContributeQuarterly: 3, ContributeYearly: 12}
+
+// InvestmentInput represents the input parameters for investment calculation
+type InvestmentInput struct {
+	Principal   Money
+	AnnualYield float64
+	TaxRate     float64 // Applies to the gains only, not to the invested capital
+	Inflation   float64
+	Years       int
+
+	// Recurring contributions, such as a monthly accumulation plan (PAC)
+	Contribution          Money                 // Amount of each contribution in the first year
+	ContributionFrequency ContributionFrequency // Default: ContributeMonthly
+	ContributionIncrease  float64               // Yearly increase of the contribution in percent
+	ContributionStart     int                   // First year with contributions (default: 1)
+	ContributionEnd       int                   // Last year with contributions (default: Years)
+
+	// Costs, taken out of the holdings
+	ExpenseRatio float64 // Annual fund expense ratio (TER) in percent, charged monthly
+	EntryFee     float64 // Commission on each amount invested, in percent
+	ExitFee      float64 // Commission on the final value when sold, in percent
+	CustodyFee   Money   // Fixed fee charged at the end of every year
+	StampDuty    float64 // Yearly tax on the value of the holdings in percent, such as ItalianStampDuty
+}
+
+// InvestmentYear is the state of the investment at the end of a year
+type InvestmentYear struct {
+	Year          int   `json:"year"`
+	Contributions Money `json:"contributions"` // Contributed during the year
+	Invested      Money `json:"invested"`      // Principal and contributions so far
+	Growth        Money `json:"growth"`        // Market growth during the year
+	Fees          Money `json:"fees"`          // Charged during the year, without the exit fee
+	Value         Money `json:"value"`         // Before tax
+	Tax           Money `json:"tax"`           // Due on the gains if sold at the end of the year
+	NetValue      Money `json:"net_value"`     // After the exit fee and tax
+	RealValue     Money `json:"real_value"`    // Net value in today's money
+}
+
+// InvestmentResult represents the output of investment calculation
+type InvestmentResult struct {
+	Principal         Money            `json:"principal"`
+	Contributions     Money            `json:"contributions"`
+	TotalInvested     Money            `json:"total_invested"`
+	FutureValue       Money            `json:"future_value"` // Before tax
+	Gains             Money            `json:"gains"`
+	NetFutureValue    Money            `json:"net_future_value"`
+	RealValue         Money            `json:"real_value"`
+	TaxPaid           Money            `json:"tax_paid"`
+	FeesPaid          Money            `json:"fees_paid"`           // Running fees and the exit fee
+	FeeDrag           Money            `json:"fee_drag"`            // Net future value lost to fees
+	FeeDragPercentage float64          `json:"fee_drag_percentage"` // Of the net future value without fees
+	Years             int              `json:"years"`
+	YearlyDetails     []InvestmentYear `json:"yearly_details"`
+}
+
+// Validate checks that the investment parameters are within range
+func (input InvestmentInput) Validate() error {
+	if input.Principal.IsNegative() {
+		return invalidInput("Principal", "initial amount must not be negative")
+	}
+	if input.AnnualYield <= -100 {
+		return invalidInput("AnnualYield", "annual yield must be greater than -100%%")
+	}
+	if err := validatePercentage("TaxRate", input.TaxRate, 0, 100); err != nil {
+		return err
+	}
+	if input.Inflation <= -100 {
+		return invalidInput("Inflation", "inflation must be greater than -100%%")
+	}
+	if input.Years < 0 {
+		return invalidInput("Years", "investment duration must not be negative")
+	}
+
+	fees := []struct {
+		field string
+		value float64
+	}{{"ExpenseRatio", input.ExpenseRatio}, {"EntryFee", input.EntryFee}, {"ExitFee", input.ExitFee}, {"StampDuty", input.StampDuty}}
+	for _, fee := range fees {
+		if err := validatePercentage(fee.field, fee.value, 0, 100); err != nil {
+			return err
+		}
+	}
+	if input.CustodyFee.IsNegative() {
+		return invalidInput("CustodyFee", "custody fee must not be negative")
+	}
+
+	if input.Contribution.IsNegative() {
+		return invalidInput("Contribution", "contribution must not be negative")
+	}
+	if _, ok := contributionMonths[input.ContributionFrequency]; !ok && input.ContributionFrequency != "" {
+		return invalidInput("ContributionFrequency", "unknown contribution frequency: %s (expected monthly, quarterly or yearly)", input.ContributionFrequency)
+	}
+	if input.ContributionIncrease <= -100 {
+		return invalidInput("ContributionIncrease", "contribution increase must be greater than -100%%")
+	}
+
+	// The contribution years only matter when there is a contribution
+	if !input.Contribution.IsPositive() {
+		return nil
+	}
+	if input.ContributionStart < 0 || input.ContributionStart > input.Years {
+		return invalidInput("ContributionStart", "contribution start year must be between 1 and %d, or 0 for the default", input.Years)
+	}
+	if input.ContributionEnd < 0 || input.ContributionEnd > input.Years {
+		return invalidInput("ContributionEnd", "contribution end year must be between 1 and %d, or 0 for the default", input.Years)
+	}
+	if input.ContributionEnd != 0 && input.ContributionEnd < input.ContributionStart {
+		return invalidInput("ContributionEnd", "contribution end year must not be before the start year")
+	}
+	return nil
+}
+
+// CalculateInvestment computes the growth of a lump sum and of recurring contributions
+// net of taxes and inflation. Contributions are invested at the start of their period
+// and the yield compounds monthly at the rate equivalent to the annual yield. Tax is
+// due on the gains only, not on the contributed capital. Fees are compared with the
+// same investment without costs.
+func CalculateInvestment(input InvestmentInput) (InvestmentResult, error) {
+	if err := input.Validate(); err != nil {
+		return InvestmentResult{}, err
+	}
+
+	market := constantMarket(input.AnnualYield, input.Inflation)
+	result := projectInvestment(input, market)
+
+	baseline := input
+	baseline.ExpenseRatio, baseline.EntryFee, baseline.ExitFee, baseline.CustodyFee, baseline.StampDuty = 0, 0, 0, Money{}, 0
+	zeroCost := projectInvestment(baseline, market).NetFutureValue
+	result.FeeDrag = zeroCost.Sub(result.NetFutureValue)
+	if zeroCost.IsPositive() {
+		result.FeeDragPercentage = result.FeeDrag.Float64() / zeroCost.Float64() * 100
+	}
+
+	return result, nil
+}
+
+// marketPath is the market an investment runs through
+type marketPath struct {
+	growth     func(month int) float64 // Growth factor of each month, counted from 0
+	priceLevel func(year int) float64  // Prices at the end of each year relative to the start
+}
+
+// constantMarket returns the same annual yield and inflation, in percent, every year
+func constantMarket(yield, inflation float64) marketPath {
+	return yearlyMarket(func(int) float64 { return yield }, inflation)
+}
+
+// yearlyMarket returns the annual yield of each year in percent, as given by yield,
+// compounded monthly at the equivalent rate, with a constant inflation
+func yearlyMarket(yield func(year int) float64, inflation float64) marketPath {
+	return marketPath{
+		growth: func(month int) float64 {
+			return math.Pow(1+yield(month/12+1)/100, 1.0/12)
+		},
+		priceLevel: func(year int) float64 {
+			return math.Pow(1+inflation/100, float64(year))
+		},
+	}
+}
+
+// projectInvestment runs the investment month by month through the market. Entry fees
+// are deducted from each amount invested, the expense ratio from the holdings every
+// month, and the stamp duty and the custody fee at the end of every year. The amounts
+// invested, fees included, are the cost basis of the gains.
+func projectInvestment(input InvestmentInput, market marketPath) InvestmentResult {
+	tax := input.TaxRate / 100
+	entryFee := input.EntryFee / 100
+	monthlyExpenses := 1 - math.Pow(1-input.ExpenseRatio/100, 1.0/12)
+
+	frequency := input.ContributionFrequency
+	if frequency == "" {
+		frequency = ContributeMonthly
+	}
+	interval := contributionMonths[frequency]
+	start := max(input.ContributionStart, 1)
+	end := input.ContributionEnd
+	if end == 0 {
+		end = input.Years
+	}
+
+	result := InvestmentResult{
+		Principal:      input.Principal,
+		TotalInvested:  input.Principal,
+		FutureValue:    input.Principal,
+		NetFutureValue: input.Principal,
+		RealValue:      input.Principal,
+		Years:          input.Years,
+		YearlyDetails:  []InvestmentYear{},
+	}
+
+	// The balance is kept unrounded so that rounding does not compound over the months
+	balance := input.Principal.Float64() * (1 - entryFee)
+	value := input.Principal
+	fees := input.Principal.Float64() * entryFee
+	for year := 1; year <= input.Years; year++ {
+		detail := InvestmentYear{Year: year}
+		opening := value
+
+		contribution := Money{}
+		if year >= start && year <= end {
+			contribution = input.Contribution.Mul(math.Pow(1+input.ContributionIncrease/100, float64(year-start))).RoundCents()
+		}
+		for month := 0; month < 12; month++ {
+			if month%interval == 0 && contribution.IsPositive() {
+				balance += contribution.Float64() * (1 - entryFee)
+				fees += contribution.Float64() * entryFee
+				detail.Contributions = detail.Contributions.Add(contribution)
+			}
+			balance *= market.growth((year-1)*12 + month)
+			fees += balance * monthlyExpenses
+			balance -= balance * monthlyExpenses
+		}
+
+		// The stamp duty is due on the value of the holdings at the end of the year
+		yearEnd := balance * input.StampDuty / 100
+		yearEnd += min(input.CustodyFee.Float64(), balance-yearEnd)
+		balance -= yearEnd
+		fees += yearEnd
+
+		value = NewMoney(balance).RoundCents()
+		detail.Fees = NewMoney(fees).RoundCents()
+		fees = 0
+
+		result.Contributions = result.Contributions.Add(detail.Contributions)
+		result.TotalInvested = result.TotalInvested.Add(detail.Contributions)
+
+		detail.Invested = result.TotalInvested
+		detail.Growth = value.Sub(opening).Sub(detail.Contributions).Add(detail.Fees)
+		detail.Value = value
+		exitFee := value.Mul(input.ExitFee / 100).RoundCents()
+		detail.Tax = MaxMoney(value.Sub(exitFee).Sub(detail.Invested), Money{}).Mul(tax).RoundCents()
+		detail.NetValue = value.Sub(exitFee).Sub(detail.Tax)
+		result.FeesPaid = result.FeesPaid.Add(detail.Fees)
+
+		// Adjust for inflation
+		detail.RealValue = detail.NetValue.Mul(1 / market.priceLevel(year)).RoundCents()
+
+		result.YearlyDetails = append(result.YearlyDetails, detail)
+	}
+
+	if len(result.YearlyDetails) > 0 {
+		last := result.YearlyDetails[len(result.YearlyDetails)-1]
+		result.FutureValue = last.Value
+		result.TaxPaid = last.Tax
+		result.NetFutureValue = last.NetValue
+		result.RealValue = last.RealValue
+		result.FeesPaid = result.FeesPaid.Add(last.Value.Mul(input.ExitFee / 100).RoundCents())
+	}
+	result.Gains = result.FutureValue.Sub(result.TotalInvested)
+
+	return result
+}
@@ -0,0 +1,437 @@
+package finance
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCalculateInvestment(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    InvestmentInput
+		expected InvestmentResult
+	}{
+		{
+			name: "Basic investment calculation",
+			input: InvestmentInput{
+				Principal:   NewMoney(10000),
+				AnnualYield: 7,
+				TaxRate:     20,
+				Inflation:   2,
+				Years:       10,
+			},
+			expected: InvestmentResult{
+				Principal:      NewMoney(10000),
+				NetFutureValue: NewMoney(17865.70), // 10000 * (1.07^10) - tax
+				RealValue:      NewMoney(14661.23), // Adjusted for 2% inflation over 10 years
+				TaxPaid:        NewMoney(1934.30),  // Tax on profit
+				Years:          10,
+			},
+		},
+		{
+			name: "Zero yield investment",
+			input: InvestmentInput{
+				Principal:   NewMoney(5000),
+				AnnualYield: 0,
+				TaxRate:     15,
+				Inflation:   3,
+				Years:       5,
+			},
+			expected: InvestmentResult{
+				Principal:      NewMoney(5000),
+				NetFutureValue: NewMoney(5000),    // No growth
+				RealValue:      NewMoney(4310.75), // Adjusted for 3% inflation over 5 years
+				TaxPaid:        NewMoney(0),       // No profit, no tax
+				Years:          5,
+			},
+		},
+		{
+			name: "High yield, long term investment",
+			input: InvestmentInput{
+				Principal:   NewMoney(1000),
+				AnnualYield: 12,
+				TaxRate:     25,
+				Inflation:   2.5,
+				Years:       30,
+			},
+			expected: InvestmentResult{
+				Principal:      NewMoney(1000),
+				NetFutureValue: NewMoney(22719.94), // 1000 * (1.12^30) - tax
+				RealValue:      NewMoney(10831.57), // Adjusted for 2.5% inflation over 30 years
+				TaxPaid:        NewMoney(7239.98),  // Tax on profit
+				Years:          30,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := CalculateInvestment(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Check basic fields
+			if result.Principal != tc.expected.Principal {
+				t.Errorf("Principal = %v, want %v", result.Principal, tc.expected.Principal)
+			}
+			if result.Years != tc.expected.Years {
+				t.Errorf("Years = %v, want %v", result.Years, tc.expected.Years)
+			}
+
+			// For floating point values, use approximate comparison
+			const tolerance = 0.01 // 1% tolerance
+
+			if !approximatelyEqual(result.NetFutureValue.Float64(), tc.expected.NetFutureValue.Float64(), tolerance) {
+				t.Errorf("NetFutureValue = %v, want approximately %v", result.NetFutureValue, tc.expected.NetFutureValue)
+			}
+			if !approximatelyEqual(result.RealValue.Float64(), tc.expected.RealValue.Float64(), tolerance) {
+				t.Errorf("RealValue = %v, want approximately %v", result.RealValue, tc.expected.RealValue)
+			}
+			if !approximatelyEqual(result.TaxPaid.Float64(), tc.expected.TaxPaid.Float64(), tolerance) {
+				t.Errorf("TaxPaid = %v, want approximately %v", result.TaxPaid, tc.expected.TaxPaid)
+			}
+		})
+	}
+}
+
+// Using approximatelyEqual function from retirement_test.go
+
+func TestInvestmentContributions(t *testing.T) {
+	tests := []struct {
+		name                  string
+		input                 InvestmentInput
+		expectedContributions float64
+		expectedValue         float64
+		expectedTax           float64
+		expectedNet           float64
+		expectedYearly        []float64 // Contributions of each year
+	}{
+		{
+			name:                  "Monthly plan without yield",
+			input:                 InvestmentInput{Contribution: NewMoney(100), TaxRate: 26, Years: 2},
+			expectedContributions: 2400,
+			expectedValue:         2400,
+			expectedTax:           0,
+			expectedNet:           2400,
+			expectedYearly:        []float64{1200, 1200},
+		},
+		{
+			name:                  "Monthly plan invested at the start of each month",
+			input:                 InvestmentInput{Contribution: NewMoney(100), AnnualYield: 12, TaxRate: 26, Years: 1},
+			expectedContributions: 1200,
+			expectedValue:         1276.65, // 100 * Σ (1.12^(1/12))^k for k = 1..12
+			expectedTax:           19.93,   // 26% of the 76.65 gain only
+			expectedNet:           1256.72,
+			expectedYearly:        []float64{1200},
+		},
+		{
+			name:                  "Yearly contributions",
+			input:                 InvestmentInput{Contribution: NewMoney(1000), ContributionFrequency: ContributeYearly, AnnualYield: 10, TaxRate: 20, Years: 3},
+			expectedContributions: 3000,
+			expectedValue:         3641, // 1000 * (1.1 + 1.1^2 + 1.1^3)
+			expectedTax:           128.20,
+			expectedNet:           3512.80,
+			expectedYearly:        []float64{1000, 1000, 1000},
+		},
+		{
+			name:                  "Quarterly contributions",
+			input:                 InvestmentInput{Contribution: NewMoney(300), ContributionFrequency: ContributeQuarterly, Years: 1},
+			expectedContributions: 1200,
+			expectedValue:         1200,
+			expectedNet:           1200,
+			expectedYearly:        []float64{1200},
+		},
+		{
+			name:                  "Yearly increase",
+			input:                 InvestmentInput{Contribution: NewMoney(1000), ContributionFrequency: ContributeYearly, ContributionIncrease: 10, Years: 3},
+			expectedContributions: 3310,
+			expectedValue:         3310,
+			expectedNet:           3310,
+			expectedYearly:        []float64{1000, 1100, 1210},
+		},
+		{
+			name:                  "Start and stop years",
+			input:                 InvestmentInput{Principal: NewMoney(500), Contribution: NewMoney(1000), ContributionFrequency: ContributeYearly, ContributionStart: 2, ContributionEnd: 3, Years: 4},
+			expectedContributions: 2000,
+			expectedValue:         2500,
+			expectedNet:           2500,
+			expectedYearly:        []float64{0, 1000, 1000, 0},
+		},
+		{
+			name:                  "No tax on a loss",
+			input:                 InvestmentInput{Principal: NewMoney(1000), Contribution: NewMoney(1000), ContributionFrequency: ContributeYearly, AnnualYield: -10, TaxRate: 26, Years: 1},
+			expectedContributions: 1000,
+			expectedValue:         1800,
+			expectedTax:           0,
+			expectedNet:           1800,
+			expectedYearly:        []float64{1000},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := CalculateInvestment(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.Contributions != NewMoney(tc.expectedContributions) {
+				t.Errorf("Contributions = %v, want %.2f", result.Contributions, tc.expectedContributions)
+			}
+			if result.TotalInvested != tc.input.Principal.Add(NewMoney(tc.expectedContributions)) {
+				t.Errorf("TotalInvested = %v, want the principal and the contributions", result.TotalInvested)
+			}
+			if result.FutureValue != NewMoney(tc.expectedValue) {
+				t.Errorf("FutureValue = %v, want %.2f", result.FutureValue, tc.expectedValue)
+			}
+			if result.TaxPaid != NewMoney(tc.expectedTax) {
+				t.Errorf("TaxPaid = %v, want %.2f", result.TaxPaid, tc.expectedTax)
+			}
+			if result.NetFutureValue != NewMoney(tc.expectedNet) {
+				t.Errorf("NetFutureValue = %v, want %.2f", result.NetFutureValue, tc.expectedNet)
+			}
+			if result.Gains != result.FutureValue.Sub(result.TotalInvested) {
+				t.Errorf("Gains = %v, want %v", result.Gains, result.FutureValue.Sub(result.TotalInvested))
+			}
+
+			if len(result.YearlyDetails) != len(tc.expectedYearly) {
+				t.Fatalf("Got %d years, want %d", len(result.YearlyDetails), len(tc.expectedYearly))
+			}
+			for i, expected := range tc.expectedYearly {
+				if result.YearlyDetails[i].Contributions != NewMoney(expected) {
+					t.Errorf("Year %d contributions = %v, want %.2f", i+1, result.YearlyDetails[i].Contributions, expected)
+				}
+			}
+			last := result.YearlyDetails[len(result.YearlyDetails)-1]
+			if last.Value != result.FutureValue || last.NetValue != result.NetFutureValue || last.RealValue != result.RealValue {
+				t.Errorf("Last year %+v does not match the result", last)
+			}
+		})
+	}
+}
+
+func TestInvestmentFees(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         InvestmentInput
+		expectedValue float64
+		expectedNet   float64
+		expectedFees  float64
+		expectedDrag  float64
+	}{
+		{
+			name:          "Expense ratio",
+			input:         InvestmentInput{Principal: NewMoney(10000), ExpenseRatio: 1, Years: 1},
+			expectedValue: 9900,
+			expectedNet:   9900,
+			expectedFees:  100,
+			expectedDrag:  100,
+		},
+		{
+			name:          "Entry and exit fees",
+			input:         InvestmentInput{Principal: NewMoney(10000), EntryFee: 2, ExitFee: 1, TaxRate: 26, Years: 1},
+			expectedValue: 9800,
+			expectedNet:   9702, // The fees are a loss, not taxed
+			expectedFees:  298,  // 200 on entry and 1% of 9800 on exit
+			expectedDrag:  298,
+		},
+		{
+			name:          "Stamp duty and custody fee",
+			input:         InvestmentInput{Principal: NewMoney(10000), StampDuty: ItalianStampDuty, CustodyFee: NewMoney(20), Years: 2},
+			expectedValue: 9920.08, // 9960 after the first year, then 0.2% of it and 20
+			expectedNet:   9920.08,
+			expectedFees:  79.92,
+			expectedDrag:  79.92,
+		},
+		{
+			name:          "Exit fee lowers the taxable gain",
+			input:         InvestmentInput{Principal: NewMoney(10000), AnnualYield: 10, ExitFee: 1, TaxRate: 26, Years: 1},
+			expectedValue: 11000,
+			expectedNet:   10658.60, // 11000 - 110 - 26% of 890
+			expectedFees:  110,
+			expectedDrag:  81.40, // 110 of fees less the 28.60 of tax they save
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CalculateInvestment(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if result.FutureValue != NewMoney(tt.expectedValue) {
+				t.Errorf("FutureValue = %v, want %.2f", result.FutureValue, tt.expectedValue)
+			}
+			if result.NetFutureValue != NewMoney(tt.expectedNet) {
+				t.Errorf("NetFutureValue = %v, want %.2f", result.NetFutureValue, tt.expectedNet)
+			}
+			if result.FeesPaid != NewMoney(tt.expectedFees) {
+				t.Errorf("FeesPaid = %v, want %.2f", result.FeesPaid, tt.expectedFees)
+			}
+			if result.FeeDrag != NewMoney(tt.expectedDrag) {
+				t.Errorf("FeeDrag = %v, want %.2f", result.FeeDrag, tt.expectedDrag)
+			}
+		})
+	}
+
+	t.Run("Fund against ETF", func(t *testing.T) {
+		plan := InvestmentInput{Principal: NewMoney(10000), Contribution: NewMoney(200), AnnualYield: 7, TaxRate: 26, Years: 20, StampDuty: ItalianStampDuty}
+
+		fund, etf := plan, plan
+		fund.ExpenseRatio, etf.ExpenseRatio = 1.5, 0.2
+		fundResult, err := CalculateInvestment(fund)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		etfResult, err := CalculateInvestment(etf)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if fundResult.FeeDrag.Cmp(etfResult.FeeDrag.Mul(3)) <= 0 {
+			t.Errorf("Fund FeeDrag = %v, want far above the ETF FeeDrag %v", fundResult.FeeDrag, etfResult.FeeDrag)
+		}
+		if fundResult.NetFutureValue.Add(fundResult.FeeDrag) != etfResult.NetFutureValue.Add(etfResult.FeeDrag) {
+			t.Errorf("The zero-cost baselines differ: %v and %v", fundResult.NetFutureValue.Add(fundResult.FeeDrag), etfResult.NetFutureValue.Add(etfResult.FeeDrag))
+		}
+		if fundResult.FeeDragPercentage < 10 || fundResult.FeeDragPercentage > 25 {
+			t.Errorf("Fund FeeDragPercentage = %.2f%%, want between 10%% and 25%%", fundResult.FeeDragPercentage)
+		}
+
+		// Yearly growth is before fees, so each year adds up
+		fees := Money{}
+		for _, year := range fundResult.YearlyDetails {
+			fees = fees.Add(year.Fees)
+			opening := year.Value.Sub(year.Growth).Sub(year.Contributions).Add(year.Fees)
+			if year.Year > 1 && opening != fundResult.YearlyDetails[year.Year-2].Value {
+				t.Errorf("Year %d does not add up: %+v", year.Year, year)
+			}
+		}
+		if fees != fundResult.FeesPaid {
+			t.Errorf("Yearly fees add up to %v, want %v", fees, fundResult.FeesPaid)
+		}
+	})
+}
+
+// TestInvestmentEdgeCases tests edge cases for the investment calculator
+func TestInvestmentEdgeCases(t *testing.T) {
+	// Test case with zero principal
+	t.Run("Zero principal", func(t *testing.T) {
+		input := InvestmentInput{
+			Principal:   NewMoney(0),
+			AnnualYield: 8,
+			TaxRate:     20,
+			Inflation:   2,
+			Years:       10,
+		}
+
+		result, err := CalculateInvestment(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !result.NetFutureValue.IsZero() {
+			t.Errorf("NetFutureValue = %v, want 0", result.NetFutureValue)
+		}
+		if !result.RealValue.IsZero() {
+			t.Errorf("RealValue = %v, want 0", result.RealValue)
+		}
+		if !result.TaxPaid.IsZero() {
+			t.Errorf("TaxPaid = %v, want 0", result.TaxPaid)
+		}
+	})
+
+	// Test case with negative yield (market downturn)
+	t.Run("Negative yield", func(t *testing.T) {
+		input := InvestmentInput{
+			Principal:   NewMoney(10000),
+			AnnualYield: -5,
+			TaxRate:     20,
+			Inflation:   2,
+			Years:       5,
+		}
+
+		result, err := CalculateInvestment(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// With negative yield, future value should be less than principal
+		if result.NetFutureValue.Cmp(input.Principal) >= 0 {
+			t.Errorf("With negative yield, NetFutureValue (%v) should be less than Principal (%v)",
+				result.NetFutureValue, input.Principal)
+		}
+
+		// Real value should be even less due to inflation
+		if result.RealValue.Cmp(result.NetFutureValue) >= 0 {
+			t.Errorf("RealValue (%v) should be less than NetFutureValue (%v) due to inflation",
+				result.RealValue, result.NetFutureValue)
+		}
+	})
+
+	// Test case with zero years
+	t.Run("Zero years", func(t *testing.T) {
+		input := InvestmentInput{
+			Principal:   NewMoney(5000),
+			AnnualYield: 6,
+			TaxRate:     15,
+			Inflation:   3,
+			Years:       0,
+		}
+
+		result, err := CalculateInvestment(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// With zero years, future value should equal principal
+		if result.NetFutureValue != input.Principal {
+			t.Errorf("NetFutureValue = %v, want %v", result.NetFutureValue, input.Principal)
+		}
+		if result.RealValue != input.Principal {
+			t.Errorf("RealValue = %v, want %v", result.RealValue, input.Principal)
+		}
+		if !result.TaxPaid.IsZero() {
+			t.Errorf("TaxPaid = %v, want 0", result.TaxPaid)
+		}
+	})
+
+	// Test cases with invalid parameters
+	invalidInputs := map[string]InvestmentInput{
+		"Negative principal":          {Principal: NewMoney(-1000), AnnualYield: 5, Years: 10},
+		"Negative years":              {Principal: NewMoney(1000), AnnualYield: 5, Years: -1},
+		"Tax above 100%":              {Principal: NewMoney(1000), AnnualYield: 5, TaxRate: 120, Years: 10},
+		"Total loss yield":            {Principal: NewMoney(1000), AnnualYield: -100, Years: 10},
+		"Negative contribution":       {Contribution: NewMoney(-100), Years: 10},
+		"Unknown frequency":           {Contribution: NewMoney(100), ContributionFrequency: "weekly", Years: 10},
+		"Contributions after the end": {Contribution: NewMoney(100), ContributionStart: 11, Years: 10},
+		"Negative start year":         {Contribution: NewMoney(100), ContributionStart: -1, Years: 10},
+		"End after the last year":     {Contribution: NewMoney(100), ContributionEnd: 11, Years: 10},
+		"Stop before the start":       {Contribution: NewMoney(100), ContributionStart: 5, ContributionEnd: 3, Years: 10},
+		"Negative expense ratio":      {Principal: NewMoney(1000), ExpenseRatio: -0.2, Years: 10},
+		"Exit fee above 100%":         {Principal: NewMoney(1000), ExitFee: 150, Years: 10},
+		"Negative custody fee":        {Principal: NewMoney(1000), CustodyFee: NewMoney(-20), Years: 10},
+	}
+	for name, input := range invalidInputs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := CalculateInvestment(input); err == nil {
+				t.Error("Expected a validation error, got nil")
+			}
+		})
+	}
+
+	t.Run("Start year out of range", func(t *testing.T) {
+		_, err := CalculateInvestment(InvestmentInput{Contribution: NewMoney(100), ContributionStart: 12, Years: 10})
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Field != "ContributionStart" {
+			t.Fatalf("Expected a ContributionStart ValidationError, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "between 1 and 10, or 0 for the default") {
+			t.Errorf("Error = %q, want the accepted range", err)
+		}
+	})
+}
@@ -0,0 +1,493 @@
+package finance
+
+import (
+	"fmt"
+	"math"
+)
+
+// PrepaymentMode selects how a prepayment changes the rest of the loan
+type PrepaymentMode string
+
+const (
+	ReduceTerm        PrepaymentMode = "term"        // Keep the installment, pay off earlier
+	ReduceInstallment PrepaymentMode = "installment" // Keep the term, lower the installment
+)
+
+// AmortizationMethod selects how the principal is repaid over the term
+type AmortizationMethod string
+
+const (
+	French       AmortizationMethod = "french"        // Constant installment
+	Italian      AmortizationMethod = "italian"       // Constant principal, decreasing installment
+	InterestOnly AmortizationMethod = "interest-only" // Interest only, principal repaid with a balloon payment
+	Bullet       AmortizationMethod = "bullet"        // Principal and capitalized interest repaid at maturity
+)
+
+// Prepayment represents a one-off payment of principal in a given month
+type Prepayment struct {
+	Month  int
+	Amount Money
+	Mode   PrepaymentMode
+}
+
+// RatePeriod represents an annual rate in percent applied to a range of months (inclusive)
+type RatePeriod struct {
+	FromMonth int
+	ToMonth   int // 0 = until the end of the loan
+	Rate      float64
+}
+
+// LoanCosts represents the fees and insurance charged on top of the interest
+type LoanCosts struct {
+	OriginationFee   Money
+	AppraisalFee     Money
+	NotaryFee        Money
+	MonthlyFee       Money // Fee charged with each installment
+	MonthlyInsurance Money // Mandatory insurance premium charged with each installment
+}
+
+// LoanInput represents the input parameters for loan calculation
+type LoanInput struct {
+	Principal    Money
+	Rate         float64
+	Years        int
+	Method       AmortizationMethod // Defaults to French
+	Monthly      bool
+	Yearly       bool  // Aggregate the schedule into yearly rows
+	FromMonth    int   // First month of the schedule to report (0 = first payment)
+	ToMonth      int   // Last month of the schedule to report (0 = last payment)
+	ExtraPayment Money // Extra principal paid every month on top of the installment
+	Prepayments  []Prepayment
+	RatePath     []RatePeriod // Rates for ranges of months, overriding Rate and the index
+	IndexSeries  []RatePeriod // Base index (e.g. Euribor) for ranges of months, plus Spread
+	Spread       float64      // Spread in percent added to the index
+	RateCap      float64      // Maximum indexed rate in percent (0 = no cap)
+	RateFloor    float64      // Minimum indexed rate in percent
+	Costs        LoanCosts
+}
+
+// MonthlyBreakdown represents a single month's payment breakdown
+type MonthlyBreakdown struct {
+	Month            int     `json:"month"`
+	Rate             float64 `json:"rate"` // Annual rate in percent applied in the month
+	Payment          Money   `json:"payment"`
+	PrincipalPayment Money   `json:"principal_payment"`
+	InterestPayment  Money   `json:"interest_payment"`
+	ExtraPayment     Money   `json:"extra_payment"`
+	RemainingBalance Money   `json:"remaining_balance"`
+}
+
+// YearlyBreakdown represents the payments of a single year of the loan
+type YearlyBreakdown struct {
+	Year             int   `json:"year"`
+	Payment          Money `json:"payment"`
+	PrincipalPayment Money `json:"principal_payment"`
+	InterestPayment  Money `json:"interest_payment"`
+	ExtraPayment     Money `json:"extra_payment"`
+	RemainingBalance Money `json:"remaining_balance"`
+}
+
+// LoanResult represents the output of loan calculation
+type LoanResult struct {
+	Principal        Money              `json:"principal"`
+	Method           AmortizationMethod `json:"method"`
+	MonthlyPayment   Money              `json:"monthly_payment"` // First installment (constant for the French method)
+	FinalPayment     Money              `json:"final_payment"`   // Last installment, including any balloon
+	TotalPaid        Money              `json:"total_paid"`
+	TotalInterest    Money              `json:"total_interest"`
+	TotalFees        Money              `json:"total_fees"` // Upfront and recurring fees, including insurance
+	APR              float64            `json:"apr"`        // Annual percentage rate (TAEG) in percent, including fees
+	Years            int                `json:"years"`
+	NumberOfPayments int                `json:"number_of_payments"`
+	PayoffMonth      int                `json:"payoff_month"`   // Month of the last payment
+	InterestSaved    Money              `json:"interest_saved"` // Interest saved by extra payments compared with the plain loan
+	MonthsSaved      int                `json:"months_saved"`   // Payments saved by extra payments compared with the plain loan
+	MonthlyDetails   []MonthlyBreakdown `json:"monthly_details"`
+	YearlyDetails    []YearlyBreakdown  `json:"yearly_details"`
+}
+
+// Validate checks that the loan parameters are consistent
+func (input LoanInput) Validate() error {
+	numberOfPayments := input.Years * 12
+
+	if input.Principal.IsNegative() {
+		return invalidInput("Principal", "loan amount must not be negative")
+	}
+	if input.Rate < 0 {
+		return invalidInput("Rate", "interest rate must not be negative")
+	}
+	if input.Years < 1 {
+		return invalidInput("Years", "loan term must be at least one year")
+	}
+	switch input.Method {
+	case "", French, Italian, InterestOnly, Bullet:
+	default:
+		return invalidInput("Method", "unknown amortization method: %s", input.Method)
+	}
+	if input.FromMonth < 0 || input.ToMonth < 0 {
+		return invalidInput("FromMonth", "schedule months must not be negative")
+	}
+	if input.ToMonth > 0 && input.FromMonth > input.ToMonth {
+		return invalidInput("FromMonth", "schedule range starts after it ends")
+	}
+	if input.ExtraPayment.IsNegative() {
+		return invalidInput("ExtraPayment", "extra payment must not be negative")
+	}
+	for _, prepayment := range input.Prepayments {
+		if prepayment.Month < 1 || prepayment.Month > numberOfPayments {
+			return invalidInput("Prepayments", "prepayment month %d is outside the loan term", prepayment.Month)
+		}
+		if prepayment.Amount.IsNegative() {
+			return invalidInput("Prepayments", "prepayment amount must not be negative")
+		}
+		if prepayment.Mode != "" && prepayment.Mode != ReduceTerm && prepayment.Mode != ReduceInstallment {
+			return invalidInput("Prepayments", "unknown prepayment mode: %s", prepayment.Mode)
+		}
+	}
+	for _, periods := range [][]RatePeriod{input.RatePath, input.IndexSeries} {
+		for _, period := range periods {
+			if period.FromMonth < 1 || (period.ToMonth != 0 && period.ToMonth < period.FromMonth) {
+				return invalidInput("RatePath", "invalid rate period %d-%d", period.FromMonth, period.ToMonth)
+			}
+		}
+	}
+	for _, period := range input.RatePath {
+		if period.Rate < 0 {
+			return invalidInput("RatePath", "interest rate must not be negative")
+		}
+	}
+	if input.RateFloor < 0 {
+		return invalidInput("RateFloor", "rate floor must not be negative")
+	}
+	if input.RateCap < 0 || (input.RateCap > 0 && input.RateCap < input.RateFloor) {
+		return invalidInput("RateCap", "rate cap must not be below the rate floor")
+	}
+	costs := input.Costs
+	for _, fee := range []Money{costs.OriginationFee, costs.AppraisalFee, costs.NotaryFee, costs.MonthlyFee, costs.MonthlyInsurance} {
+		if fee.IsNegative() {
+			return invalidInput("Costs", "fees must not be negative")
+		}
+	}
+	if upfront := SumMoney(costs.OriginationFee, costs.AppraisalFee, costs.NotaryFee); upfront.IsPositive() && upfront.Cmp(input.Principal) >= 0 {
+		return invalidInput("Costs", "upfront fees of %s leave nothing of the loan amount of %s", upfront, input.Principal)
+	}
+
+	return nil
+}
+
+// CalculateLoan computes the payments and the amortization schedule of a loan
+func CalculateLoan(input LoanInput) (LoanResult, error) {
+	if err := input.Validate(); err != nil {
+		return LoanResult{}, err
+	}
+	if input.Method == "" {
+		input.Method = French
+	}
+
+	monthlyRate := rateForMonth(input, 1) / 100 / 12
+	numberOfPayments := input.Years * 12
+
+	schedule := amortize(input)
+	totalPaid, totalInterest := scheduleTotals(schedule)
+
+	result := LoanResult{
+		Principal:        input.Principal,
+		Method:           input.Method,
+		MonthlyPayment:   annuityPayment(input.Principal, monthlyRate, numberOfPayments),
+		TotalPaid:        totalPaid,
+		TotalInterest:    totalInterest,
+		Years:            input.Years,
+		NumberOfPayments: numberOfPayments,
+		PayoffMonth:      len(schedule),
+		MonthlyDetails:   []MonthlyBreakdown{},
+		YearlyDetails:    []YearlyBreakdown{},
+	}
+
+	var err error
+	if result.TotalFees, result.APR, err = loanCost(input, schedule); err != nil {
+		return LoanResult{}, err
+	}
+
+	if len(schedule) > 0 {
+		if input.Method != French {
+			result.MonthlyPayment = schedule[0].Payment
+		}
+		result.FinalPayment = schedule[len(schedule)-1].Payment
+	}
+
+	// Compare with the same loan without any extra payment
+	if input.ExtraPayment.IsPositive() || len(input.Prepayments) > 0 {
+		plain := input
+		plain.ExtraPayment = Money{}
+		plain.Prepayments = nil
+
+		baseline := amortize(plain)
+		_, baselineInterest := scheduleTotals(baseline)
+		result.InterestSaved = baselineInterest.Sub(totalInterest)
+		result.MonthsSaved = len(baseline) - len(schedule)
+	}
+
+	details := monthRange(schedule, input.FromMonth, input.ToMonth)
+	if input.Monthly {
+		result.MonthlyDetails = details
+	}
+	if input.Yearly {
+		result.YearlyDetails = aggregateYearly(details)
+	}
+
+	return result, nil
+}
+
+// annuityPayment returns the constant installment repaying the principal in the given number of months
+func annuityPayment(principal Money, monthlyRate float64, numberOfPayments int) Money {
+	if monthlyRate == 0 {
+		return principal.Mul(1 / float64(numberOfPayments)).RoundCents()
+	}
+
+	// Monthly payment formula: P * r * (1+r)^n / ((1+r)^n - 1)
+	growth := math.Pow(1+monthlyRate, float64(numberOfPayments))
+	return principal.Mul(monthlyRate * growth / (growth - 1)).RoundCents()
+}
+
+// amortize builds the payment schedule of the loan with its amortization method, applying
+// the recurring extra payment and the prepayments until the balance is repaid.
+// The last payment absorbs the accumulated rounding so the loan closes at zero.
+func amortize(input LoanInput) []MonthlyBreakdown {
+	numberOfPayments := input.Years * 12
+	rate := rateForMonth(input, 1)
+
+	schedule := make([]MonthlyBreakdown, 0, numberOfPayments)
+	balance := input.Principal
+	capitalized := Money{} // Interest added to the balance of a bullet loan
+
+	payment := annuityPayment(balance, rate/100/12, numberOfPayments)
+	principalQuota := balance.Mul(1 / float64(numberOfPayments)).RoundCents()
+
+	for month := 1; month <= numberOfPayments; month++ {
+		lastMonth := month == numberOfPayments
+
+		// Recompute the installment over the remaining term when the rate resets,
+		// keeping the months already cut by extra payments and prepayments
+		if monthRate := rateForMonth(input, month); monthRate != rate {
+			outstanding := balance.Sub(capitalized)
+			remaining := remainingPayments(outstanding, payment, rate/100/12, numberOfPayments-month+1)
+			rate = monthRate
+			payment = annuityPayment(outstanding, rate/100/12, remaining)
+		}
+		interestPayment := balance.Mul(rate / 100 / 12).RoundCents()
+
+		var principalPayment Money
+		switch input.Method {
+		case Italian:
+			principalPayment = principalQuota
+		case InterestOnly:
+			principalPayment = Money{}
+		case Bullet:
+			if !lastMonth {
+				balance = balance.Add(interestPayment)
+				capitalized = capitalized.Add(interestPayment)
+				interestPayment = Money{}
+			}
+			principalPayment = Money{}
+		default:
+			principalPayment = payment.Sub(interestPayment)
+		}
+		if outstanding := balance.Sub(capitalized); lastMonth || principalPayment.Cmp(outstanding) > 0 {
+			principalPayment = outstanding
+		}
+		if lastMonth {
+			// Capitalized interest is settled together with the principal at maturity
+			interestPayment = interestPayment.Add(capitalized)
+			balance = balance.Sub(capitalized)
+			capitalized = Money{}
+		}
+
+		extraPayment := input.ExtraPayment
+		recompute := false
+		for _, prepayment := range input.Prepayments {
+			if prepayment.Month == month {
+				extraPayment = extraPayment.Add(prepayment.Amount)
+				recompute = recompute || prepayment.Mode == ReduceInstallment
+			}
+		}
+		extraPayment = MinMoney(extraPayment, balance.Sub(capitalized).Sub(principalPayment))
+
+		balance = balance.Sub(principalPayment).Sub(extraPayment)
+
+		schedule = append(schedule, MonthlyBreakdown{
+			Month:            month,
+			Rate:             rate,
+			Payment:          principalPayment.Add(interestPayment),
+			PrincipalPayment: principalPayment,
+			InterestPayment:  interestPayment,
+			ExtraPayment:     extraPayment,
+			RemainingBalance: balance,
+		})
+
+		if !balance.IsPositive() {
+			break
+		}
+		if recompute {
+			remaining := numberOfPayments - month
+			payment = annuityPayment(balance, rate/100/12, remaining)
+			principalQuota = balance.Mul(1 / float64(remaining)).RoundCents()
+		}
+	}
+
+	return schedule
+}
+
+// remainingPayments returns the number of installments of the given amount that repay
+// the balance at the monthly rate, capped at the payments left in the contract
+func remainingPayments(balance, payment Money, monthlyRate float64, limit int) int {
+	if !payment.IsPositive() {
+		return limit
+	}
+
+	ratio := balance.Float64() / payment.Float64()
+	months := ratio
+	if monthlyRate != 0 {
+		// Solve P * (1 - (1+r)^-n) / r = B for n
+		months = -math.Log(1-monthlyRate*ratio) / math.Log(1+monthlyRate)
+	}
+	if math.IsNaN(months) || math.IsInf(months, 0) {
+		return limit
+	}
+
+	// The tolerance absorbs the rounding of the installment to the cent
+	return min(max(int(math.Ceil(months-1e-2)), 1), limit)
+}
+
+// rateForMonth returns the annual rate in percent applied in the given month.
+// The rate path takes precedence over the index series, which falls back to the fixed rate.
+func rateForMonth(input LoanInput, month int) float64 {
+	if rate, ok := periodRate(input.RatePath, month); ok {
+		return rate
+	}
+
+	if index, ok := periodRate(input.IndexSeries, month); ok {
+		rate := math.Max(index+input.Spread, input.RateFloor)
+		if input.RateCap > 0 {
+			rate = math.Min(rate, input.RateCap)
+		}
+		return rate
+	}
+
+	return input.Rate
+}
+
+// periodRate returns the rate of the period covering the given month
+func periodRate(periods []RatePeriod, month int) (float64, bool) {
+	for _, period := range periods {
+		if month >= period.FromMonth && (period.ToMonth == 0 || month <= period.ToMonth) {
+			return period.Rate, true
+		}
+	}
+	return 0, false
+}
+
+// loanCost returns the total fees of the loan and its annual percentage rate, the
+// effective annual rate at which the actual cash flows of the borrower net to zero.
+// Without an amount borrowed there is no rate, and the APR is zero.
+func loanCost(input LoanInput, schedule []MonthlyBreakdown) (totalFees Money, apr float64, err error) {
+	costs := input.Costs
+	upfront := SumMoney(costs.OriginationFee, costs.AppraisalFee, costs.NotaryFee)
+	recurring := costs.MonthlyFee.Add(costs.MonthlyInsurance)
+
+	cashFlows := make([]float64, 0, len(schedule)+1)
+	cashFlows = append(cashFlows, input.Principal.Sub(upfront).Float64())
+	for _, detail := range schedule {
+		cashFlows = append(cashFlows, -SumMoney(detail.Payment, detail.ExtraPayment, recurring).Float64())
+	}
+
+	totalFees = upfront.Add(recurring.Mul(float64(len(schedule))))
+	if len(schedule) == 0 || !input.Principal.IsPositive() {
+		return totalFees, 0, nil
+	}
+
+	monthlyRate, err := internalRateOfReturn(cashFlows)
+	if err != nil {
+		return Money{}, 0, err
+	}
+	apr = math.Round((math.Pow(1+monthlyRate, 12)-1)*100*1e6) / 1e6
+	if apr == 0 {
+		apr = 0 // Avoid reporting a negative zero
+	}
+	return totalFees, apr, nil
+}
+
+// internalRateOfReturn returns the periodic rate at which the net present value of
+// the cash flows is zero, found by bisection. It fails when the net present value
+// does not change sign between -99% and 100%.
+func internalRateOfReturn(cashFlows []float64) (float64, error) {
+	presentValue := func(rate float64) float64 {
+		value := 0.0
+		for period, cashFlow := range cashFlows {
+			value += cashFlow / math.Pow(1+rate, float64(period))
+		}
+		return value
+	}
+
+	low, high := -0.99, 1.0
+	lowValue := presentValue(low)
+	if highValue := presentValue(high); lowValue*highValue > 0 || (lowValue == 0 && highValue == 0) {
+		return 0, fmt.Errorf("the cash flows have no rate of return")
+	}
+
+	for high-low > 1e-12 {
+		mid := (low + high) / 2
+		midValue := presentValue(mid)
+		if lowValue*midValue <= 0 {
+			high = mid
+		} else {
+			low, lowValue = mid, midValue
+		}
+	}
+
+	return (low + high) / 2, nil
+}
+
+// scheduleTotals returns the total amount paid and the total interest of a schedule
+func scheduleTotals(schedule []MonthlyBreakdown) (totalPaid, totalInterest Money) {
+	for _, detail := range schedule {
+		totalPaid = SumMoney(totalPaid, detail.Payment, detail.ExtraPayment)
+		totalInterest = totalInterest.Add(detail.InterestPayment)
+	}
+	return totalPaid, totalInterest
+}
+
+// monthRange returns the part of the schedule between the given months (inclusive).
+// A zero bound means the schedule is not limited on that side.
+func monthRange(schedule []MonthlyBreakdown, from, to int) []MonthlyBreakdown {
+	if from < 1 {
+		from = 1
+	}
+	if to < 1 || to > len(schedule) {
+		to = len(schedule)
+	}
+	if from > to {
+		return []MonthlyBreakdown{}
+	}
+	return schedule[from-1 : to]
+}
+
+// aggregateYearly sums the monthly rows of each loan year
+func aggregateYearly(schedule []MonthlyBreakdown) []YearlyBreakdown {
+	years := []YearlyBreakdown{}
+
+	for _, detail := range schedule {
+		year := (detail.Month-1)/12 + 1
+		if len(years) == 0 || years[len(years)-1].Year != year {
+			years = append(years, YearlyBreakdown{Year: year})
+		}
+
+		current := &years[len(years)-1]
+		current.Payment = current.Payment.Add(detail.Payment)
+		current.PrincipalPayment = current.PrincipalPayment.Add(detail.PrincipalPayment)
+		current.InterestPayment = current.InterestPayment.Add(detail.InterestPayment)
+		current.ExtraPayment = current.ExtraPayment.Add(detail.ExtraPayment)
+		current.RemainingBalance = detail.RemainingBalance
+	}
+
+	return years
+}
@@ -0,0 +1,63 @@
+package finance
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// LoadRateSeries reads a base-index series from a CSV file with month,rate rows.
+// Each rate applies from its month until the month before the next row; the last
+// rate applies until the end of the loan.
+func LoadRateSeries(path string) ([]RatePeriod, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseRateSeries(file)
+}
+
+// ParseRateSeries reads a base-index series in CSV format. A header row is allowed.
+func ParseRateSeries(r io.Reader) ([]RatePeriod, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	series := []RatePeriod{}
+	for i, record := range records {
+		month, err := strconv.Atoi(strings.TrimSpace(record[0]))
+		if err != nil {
+			if i == 0 {
+				continue // Header row
+			}
+			return nil, fmt.Errorf("line %d: invalid month %q", i+1, record[0])
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid rate %q", i+1, record[1])
+		}
+		if month < 1 {
+			return nil, fmt.Errorf("line %d: month must be at least 1", i+1)
+		}
+		series = append(series, RatePeriod{FromMonth: month, Rate: rate})
+	}
+
+	sort.Slice(series, func(i, j int) bool { return series[i].FromMonth < series[j].FromMonth })
+	for i := 0; i < len(series)-1; i++ {
+		series[i].ToMonth = series[i+1].FromMonth - 1
+	}
+
+	return series, nil
+}
@@ -0,0 +1,142 @@
+package finance
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRateSeries(t *testing.T) {
+	t.Run("Series with header", func(t *testing.T) {
+		series, err := ParseRateSeries(strings.NewReader("month,rate\n13,3.1\n1,3.5\n25,2.8\n"))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		expected := []RatePeriod{
+			{FromMonth: 1, ToMonth: 12, Rate: 3.5},
+			{FromMonth: 13, ToMonth: 24, Rate: 3.1},
+			{FromMonth: 25, ToMonth: 0, Rate: 2.8},
+		}
+		if len(series) != len(expected) {
+			t.Fatalf("Expected %d periods, got %d", len(expected), len(series))
+		}
+		for i := range expected {
+			if series[i] != expected[i] {
+				t.Errorf("Period %d = %+v, want %+v", i, series[i], expected[i])
+			}
+		}
+	})
+
+	t.Run("Invalid rate", func(t *testing.T) {
+		if _, err := ParseRateSeries(strings.NewReader("1,abc\n")); err == nil {
+			t.Error("Expected an error for an invalid rate")
+		}
+	})
+
+	t.Run("Invalid month", func(t *testing.T) {
+		if _, err := ParseRateSeries(strings.NewReader("1,3.5\n0,2.5\n")); err == nil {
+			t.Error("Expected an error for month 0")
+		}
+	})
+}
+
+func TestVariableRateLoan(t *testing.T) {
+	t.Run("Fixed then variable", func(t *testing.T) {
+		input := LoanInput{
+			Principal:   NewMoney(100000),
+			Rate:        3,
+			Years:       20,
+			Monthly:     true,
+			RatePath:    []RatePeriod{{FromMonth: 1, ToMonth: 60, Rate: 3}},
+			IndexSeries: []RatePeriod{{FromMonth: 1, ToMonth: 120, Rate: 3.5}, {FromMonth: 121, Rate: 0.5}},
+			Spread:      1,
+		}
+
+		result, err := CalculateLoan(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		fixed, err := CalculateLoan(LoanInput{Principal: NewMoney(100000), Rate: 3, Years: 20, Monthly: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.MonthlyDetails[59].Rate != 3 || result.MonthlyDetails[60].Rate != 4.5 || result.MonthlyDetails[120].Rate != 1.5 {
+			t.Errorf("Rates = %v, %v, %v, want 3, 4.5, 1.5",
+				result.MonthlyDetails[59].Rate, result.MonthlyDetails[60].Rate, result.MonthlyDetails[120].Rate)
+		}
+		if result.MonthlyDetails[59].Payment != fixed.MonthlyPayment {
+			t.Errorf("Fixed period payment = %v, want %v", result.MonthlyDetails[59].Payment, fixed.MonthlyPayment)
+		}
+
+		// The installment is recomputed over the remaining term at each reset
+		balance := result.MonthlyDetails[59].RemainingBalance
+		expected := annuityPayment(balance, 4.5/100/12, 180)
+		if result.MonthlyDetails[60].Payment != expected {
+			t.Errorf("Payment after reset = %v, want %v", result.MonthlyDetails[60].Payment, expected)
+		}
+		if result.MonthlyDetails[120].Payment.Cmp(result.MonthlyDetails[119].Payment) >= 0 {
+			t.Errorf("Payment should drop when the rate falls: %v >= %v",
+				result.MonthlyDetails[120].Payment, result.MonthlyDetails[119].Payment)
+		}
+
+		if result.PayoffMonth != 240 || !result.MonthlyDetails[239].RemainingBalance.IsZero() {
+			t.Errorf("Loan should close at month 240, closed at %d with %v left",
+				result.PayoffMonth, result.MonthlyDetails[239].RemainingBalance)
+		}
+	})
+
+	t.Run("Cap and floor", func(t *testing.T) {
+		input := LoanInput{
+			Principal:   NewMoney(50000),
+			Years:       3,
+			Monthly:     true,
+			IndexSeries: []RatePeriod{{FromMonth: 1, ToMonth: 12, Rate: -0.5}, {FromMonth: 13, ToMonth: 24, Rate: 6}, {FromMonth: 25, Rate: 2}},
+			Spread:      1,
+			RateCap:     5,
+			RateFloor:   1,
+		}
+
+		result, err := CalculateLoan(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.MonthlyDetails[0].Rate != 1 {
+			t.Errorf("Floored rate = %v, want 1", result.MonthlyDetails[0].Rate)
+		}
+		if result.MonthlyDetails[12].Rate != 5 {
+			t.Errorf("Capped rate = %v, want 5", result.MonthlyDetails[12].Rate)
+		}
+		if result.MonthlyDetails[24].Rate != 3 {
+			t.Errorf("Indexed rate = %v, want 3", result.MonthlyDetails[24].Rate)
+		}
+	})
+	t.Run("Reset after a term-reducing prepayment", func(t *testing.T) {
+		input := LoanInput{
+			Principal:   NewMoney(24000),
+			Rate:        6,
+			Years:       2,
+			Monthly:     true,
+			Prepayments: []Prepayment{{Month: 3, Amount: NewMoney(8000), Mode: ReduceTerm}},
+		}
+		fixed, err := CalculateLoan(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		input.RatePath = []RatePeriod{{FromMonth: 13, Rate: 6.01}}
+		result, err := CalculateLoan(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// The reset keeps the shortened term instead of stretching the loan to maturity
+		if result.PayoffMonth != fixed.PayoffMonth {
+			t.Errorf("PayoffMonth = %v, want %v", result.PayoffMonth, fixed.PayoffMonth)
+		}
+		if last := result.MonthlyDetails[len(result.MonthlyDetails)-1]; !last.RemainingBalance.IsZero() {
+			t.Errorf("Final balance = %v, want 0", last.RemainingBalance)
+		}
+	})
+}
@@ -0,0 +1,635 @@
+package finance
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestCalculateLoan(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    LoanInput
+		expected LoanResult
+	}{
+		{
+			name: "Basic mortgage calculation",
+			input: LoanInput{
+				Principal: NewMoney(300000),
+				Rate:      4.5,
+				Years:     30,
+				Monthly:   false,
+			},
+			expected: LoanResult{
+				Principal:        NewMoney(300000),
+				MonthlyPayment:   NewMoney(1520.06),   // Calculated with standard mortgage formula
+				TotalPaid:        NewMoney(547221.60), // Monthly payment * number of payments
+				TotalInterest:    NewMoney(247221.60), // Total paid - principal
+				Years:            30,
+				NumberOfPayments: 360,
+			},
+		},
+		{
+			name: "Short-term loan",
+			input: LoanInput{
+				Principal: NewMoney(20000),
+				Rate:      6.0,
+				Years:     5,
+				Monthly:   false,
+			},
+			expected: LoanResult{
+				Principal:        NewMoney(20000),
+				MonthlyPayment:   NewMoney(386.66),   // Calculated with standard loan formula
+				TotalPaid:        NewMoney(23199.60), // Monthly payment * number of payments
+				TotalInterest:    NewMoney(3199.60),  // Total paid - principal
+				Years:            5,
+				NumberOfPayments: 60,
+			},
+		},
+		{
+			name: "High interest loan",
+			input: LoanInput{
+				Principal: NewMoney(10000),
+				Rate:      12.0,
+				Years:     3,
+				Monthly:   true, // Include monthly breakdown
+			},
+			expected: LoanResult{
+				Principal:        NewMoney(10000),
+				MonthlyPayment:   NewMoney(332.14),   // Calculated with standard loan formula
+				TotalPaid:        NewMoney(11957.04), // Monthly payment * number of payments
+				TotalInterest:    NewMoney(1957.04),  // Total paid - principal
+				Years:            3,
+				NumberOfPayments: 36,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := CalculateLoan(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			// Check basic fields
+			if result.Principal != tc.expected.Principal {
+				t.Errorf("Principal = %v, want %v", result.Principal, tc.expected.Principal)
+			}
+			if result.Years != tc.expected.Years {
+				t.Errorf("Years = %v, want %v", result.Years, tc.expected.Years)
+			}
+			if result.NumberOfPayments != tc.expected.NumberOfPayments {
+				t.Errorf("NumberOfPayments = %v, want %v", result.NumberOfPayments, tc.expected.NumberOfPayments)
+			}
+
+			// For floating point values, use approximate comparison
+			const tolerance = 0.01 // 1% tolerance
+
+			if !approximatelyEqual(result.MonthlyPayment.Float64(), tc.expected.MonthlyPayment.Float64(), tolerance) {
+				t.Errorf("MonthlyPayment = %v, want approximately %v", result.MonthlyPayment, tc.expected.MonthlyPayment)
+			}
+			if !approximatelyEqual(result.TotalPaid.Float64(), tc.expected.TotalPaid.Float64(), tolerance) {
+				t.Errorf("TotalPaid = %v, want approximately %v", result.TotalPaid, tc.expected.TotalPaid)
+			}
+			if !approximatelyEqual(result.TotalInterest.Float64(), tc.expected.TotalInterest.Float64(), tolerance) {
+				t.Errorf("TotalInterest = %v, want approximately %v", result.TotalInterest, tc.expected.TotalInterest)
+			}
+
+			// Check monthly breakdown if requested
+			if tc.input.Monthly {
+				if len(result.MonthlyDetails) != result.NumberOfPayments {
+					t.Errorf("Expected %d months of details, got %d", result.NumberOfPayments, len(result.MonthlyDetails))
+				} else {
+					// Check first month's details
+					firstMonth := result.MonthlyDetails[0]
+					if firstMonth.Month != 1 {
+						t.Errorf("First month number = %v, want 1", firstMonth.Month)
+					}
+					if !approximatelyEqual(firstMonth.Payment.Float64(), result.MonthlyPayment.Float64(), 0.001) {
+						t.Errorf("First month payment = %v, want %v", firstMonth.Payment, result.MonthlyPayment)
+					}
+
+					// Check that interest + principal = payment
+					if firstMonth.InterestPayment.Add(firstMonth.PrincipalPayment) != firstMonth.Payment {
+						t.Errorf("Interest (%v) + Principal (%v) != Payment (%v)",
+							firstMonth.InterestPayment, firstMonth.PrincipalPayment, firstMonth.Payment)
+					}
+
+					// Check that the remaining balance decreases
+					for i := 1; i < len(result.MonthlyDetails); i++ {
+						if result.MonthlyDetails[i].RemainingBalance.Cmp(result.MonthlyDetails[i-1].RemainingBalance) >= 0 {
+							t.Errorf("Remaining balance should decrease: month %d (%v) >= month %d (%v)",
+								i+1, result.MonthlyDetails[i].RemainingBalance,
+								i, result.MonthlyDetails[i-1].RemainingBalance)
+						}
+					}
+				}
+			} else {
+				if len(result.MonthlyDetails) != 0 {
+					t.Errorf("Expected no monthly details, got %d", len(result.MonthlyDetails))
+				}
+			}
+		})
+	}
+}
+
+// TestLoanEdgeCases tests edge cases for the loan calculator
+func TestLoanEdgeCases(t *testing.T) {
+	// Test case with zero interest rate
+	t.Run("Zero interest rate", func(t *testing.T) {
+		input := LoanInput{
+			Principal: NewMoney(24000),
+			Rate:      0,
+			Years:     2,
+			Monthly:   false,
+		}
+
+		result, err := CalculateLoan(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Without interest the principal is simply split across the payments
+		if result.MonthlyPayment != NewMoney(1000) {
+			t.Errorf("MonthlyPayment = %v, want 1000", result.MonthlyPayment)
+		}
+		if result.TotalPaid != NewMoney(24000) {
+			t.Errorf("TotalPaid = %v, want 24000", result.TotalPaid)
+		}
+		if !result.TotalInterest.IsZero() {
+			t.Errorf("TotalInterest = %v, want 0", result.TotalInterest)
+		}
+		if math.Abs(result.APR) > 1e-6 {
+			t.Errorf("APR = %v, want 0", result.APR)
+		}
+	})
+
+	// Test case with zero principal
+	t.Run("Zero principal", func(t *testing.T) {
+		input := LoanInput{
+			Principal: NewMoney(0),
+			Rate:      5,
+			Years:     10,
+			Monthly:   false,
+		}
+
+		result, err := CalculateLoan(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// All payment values should be zero
+		if !result.MonthlyPayment.IsZero() {
+			t.Errorf("MonthlyPayment = %v, want 0", result.MonthlyPayment)
+		}
+		if !result.TotalPaid.IsZero() {
+			t.Errorf("TotalPaid = %v, want 0", result.TotalPaid)
+		}
+		if !result.TotalInterest.IsZero() {
+			t.Errorf("TotalInterest = %v, want 0", result.TotalInterest)
+		}
+		if result.APR != 0 {
+			t.Errorf("APR = %v, want 0 without an amount borrowed", result.APR)
+		}
+	})
+
+	// Test case with zero years
+	t.Run("Zero years", func(t *testing.T) {
+		input := LoanInput{
+			Principal: NewMoney(15000),
+			Rate:      7,
+			Years:     0,
+			Monthly:   false,
+		}
+
+		_, err := CalculateLoan(input)
+
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("Expected a ValidationError, got %v", err)
+		}
+		if validationErr.Field != "Years" {
+			t.Errorf("Field = %v, want Years", validationErr.Field)
+		}
+	})
+
+	// Test cases with invalid parameters
+	invalidInputs := map[string]LoanInput{
+		"Negative principal":      {Principal: NewMoney(-1000), Rate: 5, Years: 10},
+		"Negative rate":           {Principal: NewMoney(1000), Rate: -1, Years: 10},
+		"Unknown method":          {Principal: NewMoney(1000), Rate: 5, Years: 10, Method: "german"},
+		"Inverted month range":    {Principal: NewMoney(1000), Rate: 5, Years: 10, FromMonth: 24, ToMonth: 12},
+		"Negative extra payment":  {Principal: NewMoney(1000), Rate: 5, Years: 10, ExtraPayment: NewMoney(-50)},
+		"Prepayment after term":   {Principal: NewMoney(1000), Rate: 5, Years: 1, Prepayments: []Prepayment{{Month: 13, Amount: NewMoney(100)}}},
+		"Unknown prepayment mode": {Principal: NewMoney(1000), Rate: 5, Years: 1, Prepayments: []Prepayment{{Month: 6, Amount: NewMoney(100), Mode: "x"}}},
+		"Invalid rate period":     {Principal: NewMoney(1000), Rate: 5, Years: 10, RatePath: []RatePeriod{{FromMonth: 0, Rate: 3}}},
+		"Cap below floor":         {Principal: NewMoney(1000), Rate: 5, Years: 10, RateCap: 1, RateFloor: 2},
+		"Negative fee":            {Principal: NewMoney(1000), Rate: 5, Years: 10, Costs: LoanCosts{NotaryFee: NewMoney(-1)}},
+		"Fees equal to principal": {Principal: NewMoney(1000), Rate: 5, Years: 10, Costs: LoanCosts{OriginationFee: NewMoney(600), NotaryFee: NewMoney(400)}},
+		"Fees above principal":    {Principal: NewMoney(1000), Rate: 5, Years: 10, Costs: LoanCosts{AppraisalFee: NewMoney(1500)}},
+	}
+	for name, input := range invalidInputs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := CalculateLoan(input); err == nil {
+				t.Error("Expected a validation error, got nil")
+			}
+		})
+	}
+
+	// Test case with very short loan (1 year)
+	t.Run("One year loan", func(t *testing.T) {
+		input := LoanInput{
+			Principal: NewMoney(12000),
+			Rate:      6,
+			Years:     1,
+			Monthly:   true,
+		}
+
+		result, err := CalculateLoan(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Check that we have 12 monthly details
+		if len(result.MonthlyDetails) != 12 {
+			t.Errorf("Expected 12 months of details, got %d", len(result.MonthlyDetails))
+		}
+
+		// Last month's remaining balance should be close to zero
+		if len(result.MonthlyDetails) == 12 {
+			lastMonth := result.MonthlyDetails[11]
+			if !lastMonth.RemainingBalance.IsZero() {
+				t.Errorf("Last month's remaining balance = %v, want exactly 0", lastMonth.RemainingBalance)
+			}
+		}
+	})
+}
+
+// TestLoanSchedule tests the full-term schedule, month ranges and yearly rows
+func TestLoanSchedule(t *testing.T) {
+	t.Run("Full term schedule", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(250000), Rate: 3.5, Years: 30, Monthly: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(result.MonthlyDetails) != 360 {
+			t.Fatalf("Expected 360 months of details, got %d", len(result.MonthlyDetails))
+		}
+
+		// Year 17 of the mortgage must be available
+		month := result.MonthlyDetails[16*12]
+		if month.Month != 193 {
+			t.Errorf("Month = %v, want 193", month.Month)
+		}
+
+		last := result.MonthlyDetails[359]
+		if !last.RemainingBalance.IsZero() {
+			t.Errorf("Final balance = %v, want exactly 0", last.RemainingBalance)
+		}
+
+		paid := Money{}
+		for _, detail := range result.MonthlyDetails {
+			paid = paid.Add(detail.Payment)
+		}
+		if paid != result.TotalPaid {
+			t.Errorf("Schedule payments sum to %v, want %v", paid, result.TotalPaid)
+		}
+	})
+
+	t.Run("Month range", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(100000), Rate: 4, Years: 20, Monthly: true, FromMonth: 13, ToMonth: 24})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(result.MonthlyDetails) != 12 {
+			t.Fatalf("Expected 12 months of details, got %d", len(result.MonthlyDetails))
+		}
+		if result.MonthlyDetails[0].Month != 13 || result.MonthlyDetails[11].Month != 24 {
+			t.Errorf("Range = %d..%d, want 13..24", result.MonthlyDetails[0].Month, result.MonthlyDetails[11].Month)
+		}
+	})
+
+	t.Run("Out of bounds range", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(100000), Rate: 4, Years: 1, Monthly: true, FromMonth: 10, ToMonth: 99})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(result.MonthlyDetails) != 3 {
+			t.Errorf("Expected 3 months of details, got %d", len(result.MonthlyDetails))
+		}
+	})
+
+	t.Run("Yearly rows", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(50000), Rate: 5, Years: 5, Yearly: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if len(result.MonthlyDetails) != 0 {
+			t.Errorf("Expected no monthly details, got %d", len(result.MonthlyDetails))
+		}
+		if len(result.YearlyDetails) != 5 {
+			t.Fatalf("Expected 5 yearly rows, got %d", len(result.YearlyDetails))
+		}
+
+		principal := Money{}
+		interest := Money{}
+		for _, year := range result.YearlyDetails {
+			principal = principal.Add(year.PrincipalPayment)
+			interest = interest.Add(year.InterestPayment)
+		}
+		if principal != NewMoney(50000) {
+			t.Errorf("Yearly principal sums to %v, want 50000", principal)
+		}
+		if interest != result.TotalInterest {
+			t.Errorf("Yearly interest sums to %v, want %v", interest, result.TotalInterest)
+		}
+		if !result.YearlyDetails[4].RemainingBalance.IsZero() {
+			t.Errorf("Final year-end balance = %v, want 0", result.YearlyDetails[4].RemainingBalance)
+		}
+	})
+}
+
+// TestLoanPrepayments tests recurring extra payments and one-off prepayments
+func TestLoanPrepayments(t *testing.T) {
+	baseline, err := CalculateLoan(LoanInput{Principal: NewMoney(200000), Rate: 4, Years: 25})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	t.Run("No extra payments", func(t *testing.T) {
+		if baseline.PayoffMonth != 300 {
+			t.Errorf("PayoffMonth = %v, want 300", baseline.PayoffMonth)
+		}
+		if !baseline.InterestSaved.IsZero() || baseline.MonthsSaved != 0 {
+			t.Errorf("Savings = (%v, %v), want none", baseline.InterestSaved, baseline.MonthsSaved)
+		}
+	})
+
+	t.Run("Recurring extra payment shortens the term", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(200000), Rate: 4, Years: 25, ExtraPayment: NewMoney(200), Monthly: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.MonthlyPayment != baseline.MonthlyPayment {
+			t.Errorf("MonthlyPayment = %v, want %v", result.MonthlyPayment, baseline.MonthlyPayment)
+		}
+		if result.PayoffMonth >= 300 {
+			t.Errorf("PayoffMonth = %v, want less than 300", result.PayoffMonth)
+		}
+		if result.MonthsSaved != 300-result.PayoffMonth {
+			t.Errorf("MonthsSaved = %v, want %v", result.MonthsSaved, 300-result.PayoffMonth)
+		}
+		if result.InterestSaved != baseline.TotalInterest.Sub(result.TotalInterest) {
+			t.Errorf("InterestSaved = %v, want %v", result.InterestSaved, baseline.TotalInterest.Sub(result.TotalInterest))
+		}
+		if result.InterestSaved.Cmp(NewMoney(0)) <= 0 {
+			t.Errorf("InterestSaved = %v, want positive", result.InterestSaved)
+		}
+
+		last := result.MonthlyDetails[len(result.MonthlyDetails)-1]
+		if !last.RemainingBalance.IsZero() {
+			t.Errorf("Final balance = %v, want 0", last.RemainingBalance)
+		}
+	})
+
+	t.Run("Lump sum reducing the term", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{
+			Principal:   NewMoney(200000),
+			Rate:        4,
+			Years:       25,
+			Monthly:     true,
+			Prepayments: []Prepayment{{Month: 60, Amount: NewMoney(20000), Mode: ReduceTerm}},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.MonthlyDetails[59].ExtraPayment != NewMoney(20000) {
+			t.Errorf("Month 60 extra payment = %v, want 20000", result.MonthlyDetails[59].ExtraPayment)
+		}
+		if result.MonthlyDetails[60].Payment != baseline.MonthlyPayment {
+			t.Errorf("Installment after prepayment = %v, want %v", result.MonthlyDetails[60].Payment, baseline.MonthlyPayment)
+		}
+		if result.MonthsSaved <= 0 {
+			t.Errorf("MonthsSaved = %v, want positive", result.MonthsSaved)
+		}
+	})
+
+	t.Run("Lump sum reducing the installment", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{
+			Principal:   NewMoney(200000),
+			Rate:        4,
+			Years:       25,
+			Monthly:     true,
+			Prepayments: []Prepayment{{Month: 60, Amount: NewMoney(20000), Mode: ReduceInstallment}},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.PayoffMonth != 300 || result.MonthsSaved != 0 {
+			t.Errorf("PayoffMonth = %v (saved %v), want 300 (saved 0)", result.PayoffMonth, result.MonthsSaved)
+		}
+		if result.MonthlyDetails[60].Payment.Cmp(baseline.MonthlyPayment) >= 0 {
+			t.Errorf("Installment after prepayment = %v, want less than %v", result.MonthlyDetails[60].Payment, baseline.MonthlyPayment)
+		}
+		if result.InterestSaved.Cmp(NewMoney(0)) <= 0 {
+			t.Errorf("InterestSaved = %v, want positive", result.InterestSaved)
+		}
+		if !result.MonthlyDetails[299].RemainingBalance.IsZero() {
+			t.Errorf("Final balance = %v, want 0", result.MonthlyDetails[299].RemainingBalance)
+		}
+	})
+
+	t.Run("Prepayment larger than the balance", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{
+			Principal:   NewMoney(10000),
+			Rate:        5,
+			Years:       5,
+			Monthly:     true,
+			Prepayments: []Prepayment{{Month: 12, Amount: NewMoney(50000), Mode: ReduceTerm}},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.PayoffMonth != 12 {
+			t.Errorf("PayoffMonth = %v, want 12", result.PayoffMonth)
+		}
+		if result.TotalPaid.Sub(result.TotalInterest) != NewMoney(10000) {
+			t.Errorf("Principal repaid = %v, want 10000", result.TotalPaid.Sub(result.TotalInterest))
+		}
+	})
+}
+
+// TestLoanMethods tests the supported amortization methods
+func TestLoanMethods(t *testing.T) {
+	t.Run("French is the default", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(120000), Rate: 3, Years: 10})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.Method != French {
+			t.Errorf("Method = %v, want %v", result.Method, French)
+		}
+	})
+
+	t.Run("Italian", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(120000), Rate: 3, Years: 10, Method: Italian, Monthly: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Constant principal of 1000 with interest on the remaining balance
+		first := result.MonthlyDetails[0]
+		if first.PrincipalPayment != NewMoney(1000) || first.InterestPayment != NewMoney(300) || first.Payment != NewMoney(1300) {
+			t.Errorf("First month = %+v, want principal 1000, interest 300, payment 1300", first)
+		}
+		if result.MonthlyPayment != NewMoney(1300) {
+			t.Errorf("MonthlyPayment = %v, want 1300", result.MonthlyPayment)
+		}
+
+		for i := 1; i < len(result.MonthlyDetails); i++ {
+			if result.MonthlyDetails[i].Payment.Cmp(result.MonthlyDetails[i-1].Payment) >= 0 {
+				t.Fatalf("Installment should decrease: month %d (%v) >= month %d (%v)",
+					i+1, result.MonthlyDetails[i].Payment, i, result.MonthlyDetails[i-1].Payment)
+			}
+		}
+
+		// Interest on an arithmetic series of balances: r * P * (n+1) / 2
+		if !approximatelyEqual(result.TotalInterest.Float64(), 18150, 0.0001) {
+			t.Errorf("TotalInterest = %v, want 18150", result.TotalInterest)
+		}
+		if !result.MonthlyDetails[119].RemainingBalance.IsZero() {
+			t.Errorf("Final balance = %v, want 0", result.MonthlyDetails[119].RemainingBalance)
+		}
+	})
+
+	t.Run("Interest only with balloon", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(120000), Rate: 3, Years: 10, Method: InterestOnly, Monthly: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.MonthlyPayment != NewMoney(300) {
+			t.Errorf("MonthlyPayment = %v, want 300", result.MonthlyPayment)
+		}
+		if result.FinalPayment != NewMoney(120300) {
+			t.Errorf("FinalPayment = %v, want 120300", result.FinalPayment)
+		}
+		if result.TotalInterest != NewMoney(36000) {
+			t.Errorf("TotalInterest = %v, want 36000", result.TotalInterest)
+		}
+		if result.MonthlyDetails[60].RemainingBalance != NewMoney(120000) {
+			t.Errorf("Balance before maturity = %v, want 120000", result.MonthlyDetails[60].RemainingBalance)
+		}
+	})
+
+	t.Run("Bullet", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(10000), Rate: 6, Years: 2, Method: Bullet, Monthly: true})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if !result.MonthlyPayment.IsZero() {
+			t.Errorf("MonthlyPayment = %v, want 0", result.MonthlyPayment)
+		}
+
+		// Interest compounds monthly until maturity
+		expected := 10000 * math.Pow(1.005, 24)
+		if !approximatelyEqual(result.FinalPayment.Float64(), expected, 0.05) {
+			t.Errorf("FinalPayment = %v, want approximately %v", result.FinalPayment, expected)
+		}
+		if result.TotalPaid != result.FinalPayment {
+			t.Errorf("TotalPaid = %v, want %v", result.TotalPaid, result.FinalPayment)
+		}
+
+		last := result.MonthlyDetails[23]
+		if last.PrincipalPayment != NewMoney(10000) || !last.RemainingBalance.IsZero() {
+			t.Errorf("Last month = %+v, want principal 10000 and no balance", last)
+		}
+	})
+
+	t.Run("Italian prepayment lowering the installment", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{
+			Principal:   NewMoney(120000),
+			Rate:        3,
+			Years:       10,
+			Method:      Italian,
+			Monthly:     true,
+			Prepayments: []Prepayment{{Month: 60, Amount: NewMoney(30000), Mode: ReduceInstallment}},
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.PayoffMonth != 120 {
+			t.Errorf("PayoffMonth = %v, want 120", result.PayoffMonth)
+		}
+		if result.MonthlyDetails[60].PrincipalPayment != NewMoney(500) {
+			t.Errorf("Principal after prepayment = %v, want 500", result.MonthlyDetails[60].PrincipalPayment)
+		}
+	})
+}
+
+// TestLoanAPR tests the annual percentage rate including fees and insurance
+func TestLoanAPR(t *testing.T) {
+	t.Run("No fees", func(t *testing.T) {
+		result, err := CalculateLoan(LoanInput{Principal: NewMoney(100000), Rate: 6, Years: 10})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// Effective annual rate of a 6% nominal rate compounded monthly
+		if math.Abs(result.APR-6.1678) > 0.001 {
+			t.Errorf("APR = %v, want approximately 6.1678", result.APR)
+		}
+		if !result.TotalFees.IsZero() {
+			t.Errorf("TotalFees = %v, want 0", result.TotalFees)
+		}
+	})
+
+	t.Run("Upfront and monthly costs", func(t *testing.T) {
+		input := LoanInput{
+			Principal: NewMoney(100000),
+			Rate:      3,
+			Years:     20,
+			Costs: LoanCosts{
+				OriginationFee:   NewMoney(1000),
+				AppraisalFee:     NewMoney(300),
+				NotaryFee:        NewMoney(2000),
+				MonthlyFee:       NewMoney(2),
+				MonthlyInsurance: NewMoney(20),
+			},
+		}
+
+		result, err := CalculateLoan(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if result.TotalFees != NewMoney(3300+22*240) {
+			t.Errorf("TotalFees = %v, want %v", result.TotalFees, 3300+22*240)
+		}
+		if result.APR <= 3.0416 {
+			t.Errorf("APR = %v, want above the effective nominal rate", result.APR)
+		}
+
+		// The APR must discount the actual cash flows to the net amount received
+		monthlyRate := math.Pow(1+result.APR/100, 1.0/12) - 1
+		presentValue := 0.0
+		for i := 1; i <= 240; i++ {
+			presentValue += (result.MonthlyPayment.Float64() + 22) / math.Pow(1+monthlyRate, float64(i))
+		}
+		if math.Abs(presentValue-96700) > 5 {
+			t.Errorf("Present value of payments = %v, want approximately 96700", presentValue)
+		}
+	})
+}
@@ -0,0 +1,297 @@
+package finance
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// moneyDecimals is the number of decimal places stored by Money
+const moneyDecimals = 4
+
+// moneyScale is the number of stored units in one currency unit
+const moneyScale = 10000
+
+// RoundingMode selects how ties are rounded
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // Ties to the even digit (banker's rounding)
+	RoundHalfUp                       // Ties away from zero
+)
+
+// DefaultRounding is the rounding mode used by the calculators
+const DefaultRounding = RoundHalfEven
+
+// Money is a fixed-point decimal amount with four decimal places.
+// Arithmetic on Money is exact; rounding only happens where it is explicit.
+type Money struct {
+	units int64 // Ten-thousandths of the currency unit
+}
+
+// NewMoney converts a float amount to Money, rounding half-even to four decimal places
+func NewMoney(amount float64) Money {
+	return Money{units: int64(math.RoundToEven(amount * moneyScale))}
+}
+
+// NewMoneyRounded converts a float amount to Money rounded to the given decimal places
+func NewMoneyRounded(amount float64, decimals int, mode RoundingMode) Money {
+	scale := math.Pow10(decimals)
+	return Money{units: int64(math.Round(roundFloat(amount*scale, mode) * moneyScale / scale))}
+}
+
+// ParseMoney parses a decimal amount such as "1234.56" without going through a float
+func ParseMoney(value string) (Money, error) {
+	value = strings.TrimSpace(value)
+	negative := strings.HasPrefix(value, "-")
+	digits := strings.TrimLeft(value, "+-")
+
+	whole, fraction, _ := strings.Cut(digits, ".")
+	if whole == "" && fraction == "" {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if len(fraction) > moneyDecimals {
+		return Money{}, fmt.Errorf("invalid amount %q: more than %d decimal places", value, moneyDecimals)
+	}
+	fraction += strings.Repeat("0", moneyDecimals-len(fraction))
+	if whole == "" {
+		whole = "0"
+	}
+
+	units, err := strconv.ParseInt(whole+fraction, 10, 64)
+	if err != nil {
+		return Money{}, fmt.Errorf("invalid amount %q", value)
+	}
+	if negative {
+		units = -units
+	}
+	return Money{units: units}, nil
+}
+
+// Float64 returns the amount as a float
+func (m Money) Float64() float64 {
+	return float64(m.units) / moneyScale
+}
+
+// Add returns m + other
+func (m Money) Add(other Money) Money {
+	return Money{units: m.units + other.units}
+}
+
+// Sub returns m - other
+func (m Money) Sub(other Money) Money {
+	return Money{units: m.units - other.units}
+}
+
+// Neg returns -m
+func (m Money) Neg() Money {
+	return Money{units: -m.units}
+}
+
+// Mul multiplies the amount by a factor, rounding half-even to four decimal places
+func (m Money) Mul(factor float64) Money {
+	return Money{units: int64(math.RoundToEven(float64(m.units) * factor))}
+}
+
+// Round rounds the amount to the given number of decimal places
+func (m Money) Round(decimals int, mode RoundingMode) Money {
+	if decimals >= moneyDecimals {
+		return m
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	unit := int64(math.Pow10(moneyDecimals - decimals))
+	quotient, remainder := m.units/unit, m.units%unit
+	if remainder < 0 {
+		remainder = -remainder
+	}
+
+	roundAway := false
+	switch {
+	case 2*remainder > unit:
+		roundAway = true
+	case 2*remainder == unit:
+		roundAway = mode == RoundHalfUp || quotient%2 != 0
+	}
+	if roundAway {
+		if m.units < 0 {
+			quotient--
+		} else {
+			quotient++
+		}
+	}
+
+	return Money{units: quotient * unit}
+}
+
+// RoundCents rounds the amount to two decimal places with the default rounding mode
+func (m Money) RoundCents() Money {
+	return m.Round(2, DefaultRounding)
+}
+
+// Cmp compares two amounts, returning -1, 0 or +1
+func (m Money) Cmp(other Money) int {
+	switch {
+	case m.units < other.units:
+		return -1
+	case m.units > other.units:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// IsZero reports whether the amount is zero
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+// IsNegative reports whether the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.units < 0
+}
+
+// IsPositive reports whether the amount is above zero
+func (m Money) IsPositive() bool {
+	return m.units > 0
+}
+
+// MinMoney returns the smaller of two amounts
+func MinMoney(a, b Money) Money {
+	if a.units < b.units {
+		return a
+	}
+	return b
+}
+
+// MaxMoney returns the larger of two amounts
+func MaxMoney(a, b Money) Money {
+	if a.units > b.units {
+		return a
+	}
+	return b
+}
+
+// Allocate splits the amount by percentages into cent amounts. The shares sum exactly
+// to the amount times the total percentage, rounded to cents; leftover cents go to the
+// shares with the largest remainders.
+func (m Money) Allocate(percentages []float64) []Money {
+	const centUnits = moneyScale / 100
+
+	total := 0.0
+	for _, percentage := range percentages {
+		total += percentage
+	}
+	target := m.Mul(total/100).RoundCents().units / centUnits
+
+	shares := make([]Money, len(percentages))
+	remainders := make([]float64, len(percentages))
+	allocated := int64(0)
+	for i, percentage := range percentages {
+		exact := float64(m.units) * percentage / 100 / centUnits
+		cents := int64(math.Floor(exact))
+		if exact < 0 {
+			cents = int64(math.Ceil(exact))
+		}
+		shares[i] = Money{units: cents * centUnits}
+		remainders[i] = math.Abs(exact - float64(cents))
+		allocated += cents
+	}
+
+	step := int64(1)
+	if target < allocated {
+		step = -1
+	}
+	for leftover := target - allocated; leftover != 0 && len(shares) > 0; leftover -= step {
+		largest := 0
+		for i := range remainders {
+			if remainders[i] > remainders[largest] {
+				largest = i
+			}
+		}
+		shares[largest].units += step * centUnits
+		remainders[largest] = -1
+	}
+
+	return shares
+}
+
+// SumMoney returns the sum of the amounts
+func SumMoney(amounts ...Money) Money {
+	total := Money{}
+	for _, amount := range amounts {
+		total = total.Add(amount)
+	}
+	return total
+}
+
+// String formats the amount with at least two decimal places
+func (m Money) String() string {
+	decimals := 2
+	for ; decimals < moneyDecimals; decimals++ {
+		if m.Round(decimals, DefaultRounding) == m {
+			break
+		}
+	}
+	return m.Format(decimals)
+}
+
+// Format formats the amount rounded half-even to the given number of decimal places
+func (m Money) Format(decimals int) string {
+	if decimals > moneyDecimals {
+		decimals = moneyDecimals
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+
+	units := m.Round(decimals, DefaultRounding).units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+
+	whole := units / moneyScale
+	if decimals == 0 {
+		return fmt.Sprintf("%s%d", sign, whole)
+	}
+	fraction := units % moneyScale / int64(math.Pow10(moneyDecimals-decimals))
+	return fmt.Sprintf("%s%d.%0*d", sign, whole, decimals, fraction)
+}
+
+// MarshalText encodes the amount as a decimal string
+func (m Money) MarshalText() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalText decodes a decimal string
+func (m *Money) UnmarshalText(text []byte) error {
+	parsed, err := ParseMoney(string(text))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// MarshalJSON encodes the amount as a JSON number
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON decodes a JSON number or a quoted decimal string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	return m.UnmarshalText([]byte(strings.Trim(string(data), `"`)))
+}
+
+// roundFloat rounds a float to an integer value with the given mode
+func roundFloat(value float64, mode RoundingMode) float64 {
+	if mode == RoundHalfUp {
+		return math.Round(value)
+	}
+	return math.RoundToEven(value)
+}
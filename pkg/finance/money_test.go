@@ -0,0 +1,140 @@
+package finance
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+		wantErr  bool
+	}{
+		{value: "1234.56", expected: "1234.56"},
+		{value: "0.1", expected: "0.10"},
+		{value: "-42", expected: "-42.00"},
+		{value: ".5", expected: "0.50"},
+		{value: "1.2345", expected: "1.2345"},
+		{value: "1.23456", wantErr: true},
+		{value: "abc", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.value, func(t *testing.T) {
+			amount, err := ParseMoney(tc.value)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", amount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if amount.String() != tc.expected {
+				t.Errorf("ParseMoney(%q) = %s, want %s", tc.value, amount, tc.expected)
+			}
+		})
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	// 0.1 + 0.2 is exact in fixed point
+	sum := NewMoney(0.1).Add(NewMoney(0.2))
+	if sum != NewMoney(0.3) {
+		t.Errorf("0.1 + 0.2 = %v, want 0.30", sum)
+	}
+
+	total := Money{}
+	for i := 0; i < 1000; i++ {
+		total = total.Add(NewMoney(0.01))
+	}
+	if total != NewMoney(10) {
+		t.Errorf("Sum of 1000 cents = %v, want 10.00", total)
+	}
+
+	if diff := NewMoney(5).Sub(NewMoney(7.5)); diff.String() != "-2.50" || !diff.IsNegative() {
+		t.Errorf("5 - 7.5 = %v, want -2.50", diff)
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	tests := []struct {
+		amount   float64
+		mode     RoundingMode
+		expected string
+	}{
+		{amount: 2.345, mode: RoundHalfEven, expected: "2.34"},
+		{amount: 2.355, mode: RoundHalfEven, expected: "2.36"},
+		{amount: 2.345, mode: RoundHalfUp, expected: "2.35"},
+		{amount: -2.345, mode: RoundHalfUp, expected: "-2.35"},
+		{amount: -2.345, mode: RoundHalfEven, expected: "-2.34"},
+		{amount: 2.3449, mode: RoundHalfUp, expected: "2.34"},
+	}
+
+	for _, tc := range tests {
+		rounded := NewMoney(tc.amount).Round(2, tc.mode)
+		if rounded.String() != tc.expected {
+			t.Errorf("Round(%v, mode %d) = %s, want %s", tc.amount, tc.mode, rounded, tc.expected)
+		}
+	}
+
+	if got := NewMoneyRounded(0.125, 2, RoundHalfUp).String(); got != "0.13" {
+		t.Errorf("NewMoneyRounded(0.125, half-up) = %s, want 0.13", got)
+	}
+	if got := NewMoneyRounded(0.125, 2, RoundHalfEven).String(); got != "0.12" {
+		t.Errorf("NewMoneyRounded(0.125, half-even) = %s, want 0.12", got)
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	t.Run("Shares sum to the amount", func(t *testing.T) {
+		shares := NewMoney(100).Allocate([]float64{100.0 / 3, 100.0 / 3, 100.0 / 3})
+		if SumMoney(shares...) != NewMoney(100) {
+			t.Errorf("Shares %v sum to %v, want 100.00", shares, SumMoney(shares...))
+		}
+		for _, share := range shares {
+			if share.Cmp(NewMoney(33.33)) < 0 || share.Cmp(NewMoney(33.34)) > 0 {
+				t.Errorf("Share %v, want 33.33 or 33.34", share)
+			}
+		}
+	})
+
+	t.Run("Partial allocation", func(t *testing.T) {
+		shares := NewMoney(10).Allocate([]float64{12.5, 12.5})
+		if shares[0] != NewMoney(1.25) || shares[1] != NewMoney(1.25) {
+			t.Errorf("Shares = %v, want [1.25 1.25]", shares)
+		}
+	})
+
+	t.Run("Odd cent", func(t *testing.T) {
+		shares := NewMoney(0.05).Allocate([]float64{50, 50})
+		if SumMoney(shares...) != NewMoney(0.05) {
+			t.Errorf("Shares %v sum to %v, want 0.05", shares, SumMoney(shares...))
+		}
+	})
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+	}{Amount: NewMoney(1234.5)})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if string(data) != `{"amount":1234.50}` {
+		t.Errorf("Marshal = %s, want {\"amount\":1234.50}", data)
+	}
+
+	var decoded struct {
+		Amount Money `json:"amount"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"99.99"}`), &decoded); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if decoded.Amount != NewMoney(99.99) {
+		t.Errorf("Unmarshal = %v, want 99.99", decoded.Amount)
+	}
+}
@@ -0,0 +1,266 @@
+package finance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MaxSimulations caps the number of Monte Carlo runs of a simulation
+const MaxSimulations = 1_000_000
+
+// ReturnDistribution selects how the annual returns of a simulation are drawn
+type ReturnDistribution string
+
+const (
+	NormalReturns    ReturnDistribution = "normal"    // Mean and volatility of the returns
+	LogNormalReturns ReturnDistribution = "lognormal" // Growth factors with the mean and volatility of the returns
+	BootstrapReturns ReturnDistribution = "bootstrap" // Historical returns drawn with replacement
+)
+
+// SimulationInput configures a Monte Carlo simulation. The mean return is the annual
+// yield of the calculator input.
+type SimulationInput struct {
+	Simulations  int
+	Seed         uint64             // Runs with the same seed draw the same returns
+	Workers      int                // Goroutines running the simulations (default: the number of CPUs)
+	Distribution ReturnDistribution // Default: NormalReturns
+	Volatility   float64            // Standard deviation of the annual returns in percent
+	History      []float64          // Annual returns in percent for BootstrapReturns
+	Target       Money              // Value to reach, ignored when zero
+}
+
+// SimulationResult summarizes the outcomes of a Monte Carlo simulation
+type SimulationResult struct {
+	Simulations       int                `json:"simulations"`
+	Seed              uint64             `json:"seed"`
+	Distribution      ReturnDistribution `json:"distribution"`
+	ConstantYield     Money              `json:"constant_yield"` // Outcome when every year returns the annual yield of the input
+	Mean              Money              `json:"mean"`
+	P5                Money              `json:"p5"`
+	P25               Money              `json:"p25"`
+	P50               Money              `json:"p50"`
+	P75               Money              `json:"p75"`
+	P95               Money              `json:"p95"`
+	Target            Money              `json:"target"`
+	TargetProbability float64            `json:"target_probability"` // Share of outcomes reaching the target, in percent
+}
+
+// Validate checks the number of simulations and the return distribution
+func (input SimulationInput) Validate() error {
+	if input.Simulations <= 0 || input.Simulations > MaxSimulations {
+		return invalidInput("Simulations", "number of simulations must be between 1 and %d", MaxSimulations)
+	}
+	if input.Workers < 0 {
+		return invalidInput("Workers", "number of workers must not be negative")
+	}
+	if input.Volatility < 0 {
+		return invalidInput("Volatility", "volatility must not be negative")
+	}
+	if input.Target.IsNegative() {
+		return invalidInput("Target", "target must not be negative")
+	}
+
+	switch input.Distribution {
+	case "", NormalReturns, LogNormalReturns:
+	case BootstrapReturns:
+		if len(input.History) == 0 {
+			return invalidInput("History", "bootstrap simulations need historical returns")
+		}
+		for _, r := range input.History {
+			if r < -100 {
+				return invalidInput("History", "historical return %g%% is below -100%%", r)
+			}
+		}
+	default:
+		return invalidInput("Distribution", "unknown return distribution: %s (expected normal, lognormal or bootstrap)", input.Distribution)
+	}
+	return nil
+}
+
+// SimulateInvestment runs the investment with random annual returns around its annual
+// yield and reports the distribution of the net future value
+func SimulateInvestment(input InvestmentInput, simulation SimulationInput) (SimulationResult, error) {
+	if err := input.Validate(); err != nil {
+		return SimulationResult{}, err
+	}
+
+	constant := projectInvestment(input, constantMarket(input.AnnualYield, input.Inflation)).NetFutureValue
+	return simulate(simulation, input.AnnualYield, input.Years, constant, func(returns []float64) Money {
+		market := yearlyMarket(func(year int) float64 { return returns[year-1] }, input.Inflation)
+		return projectInvestment(input, market).NetFutureValue
+	})
+}
+
+// SimulateRetirement runs the savings until retirement with random annual returns
+// around the annual yield and reports the distribution of the retirement savings
+func SimulateRetirement(input RetirementInput, simulation SimulationInput) (SimulationResult, error) {
+	result, err := CalculateRetirement(input)
+	if err != nil {
+		return SimulationResult{}, err
+	}
+
+	return simulate(simulation, input.AnnualYield, result.YearsToRetirement, result.RetirementSavings, func(returns []float64) Money {
+		return retirementSavings(input, func(year int) float64 { return returns[year-1] })
+	})
+}
+
+// simulate draws the annual returns of each run and collects the outcomes of project.
+// Every run has its own random source seeded with the seed and the run number, so the
+// outcomes do not depend on the number of workers.
+func simulate(input SimulationInput, mean float64, years int, constant Money, project func(returns []float64) Money) (SimulationResult, error) {
+	if err := input.Validate(); err != nil {
+		return SimulationResult{}, err
+	}
+
+	distribution := input.Distribution
+	if distribution == "" {
+		distribution = NormalReturns
+	}
+	draw := returnSampler(distribution, mean, input.Volatility, input.History)
+
+	workers := input.Workers
+	if workers == 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, input.Simulations)
+
+	outcomes := make([]Money, input.Simulations)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			returns := make([]float64, years)
+			for run := w; run < input.Simulations; run += workers {
+				rng := rand.New(rand.NewPCG(input.Seed, uint64(run)))
+				for year := range returns {
+					returns[year] = draw(rng)
+				}
+				outcomes[run] = project(returns)
+			}
+		}()
+	}
+	wg.Wait()
+
+	slices.SortFunc(outcomes, Money.Cmp)
+
+	result := SimulationResult{
+		Simulations:   input.Simulations,
+		Seed:          input.Seed,
+		Distribution:  distribution,
+		ConstantYield: constant,
+		Mean:          SumMoney(outcomes...).Mul(1 / float64(len(outcomes))).RoundCents(),
+		P5:            percentile(outcomes, 5),
+		P25:           percentile(outcomes, 25),
+		P50:           percentile(outcomes, 50),
+		P75:           percentile(outcomes, 75),
+		P95:           percentile(outcomes, 95),
+		Target:        input.Target,
+	}
+
+	if input.Target.IsPositive() {
+		below, _ := slices.BinarySearchFunc(outcomes, input.Target, Money.Cmp)
+		result.TargetProbability = float64(len(outcomes)-below) / float64(len(outcomes)) * 100
+	}
+
+	return result, nil
+}
+
+// returnSampler returns a function that draws an annual return in percent. Returns
+// never fall below -100%, a total loss.
+func returnSampler(distribution ReturnDistribution, mean, volatility float64, history []float64) func(*rand.Rand) float64 {
+	switch distribution {
+	case LogNormalReturns:
+		// Parameters of the log growth factor that give the arithmetic mean and volatility
+		growth := 1 + mean/100
+		sigma := math.Sqrt(math.Log(1 + math.Pow(volatility/100/growth, 2)))
+		mu := math.Log(growth) - sigma*sigma/2
+		return func(rng *rand.Rand) float64 {
+			return (math.Exp(mu+sigma*rng.NormFloat64()) - 1) * 100
+		}
+	case BootstrapReturns:
+		return func(rng *rand.Rand) float64 {
+			return history[rng.IntN(len(history))]
+		}
+	default:
+		return func(rng *rand.Rand) float64 {
+			return max(mean+volatility*rng.NormFloat64(), -100)
+		}
+	}
+}
+
+// percentile interpolates linearly between the closest ranks of sorted outcomes
+func percentile(sorted []Money, p float64) Money {
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := min(lower+1, len(sorted)-1)
+	fraction := rank - float64(lower)
+	return sorted[lower].Add(sorted[upper].Sub(sorted[lower]).Mul(fraction)).RoundCents()
+}
+
+// LoadReturns reads historical annual returns for bootstrap simulations
+func LoadReturns(path string) ([]float64, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ParseReturns(file)
+}
+
+// ParseReturns reads annual returns in percent, one per line, such as 7.5 or -12%.
+// A line may start with the year, as in 2008,-37.0; the return is the last field, or
+// the return column of a header line. Blank lines, lines of bare separators and
+// # comments are skipped.
+func ParseReturns(r io.Reader) ([]float64, error) {
+	scanner := bufio.NewScanner(r)
+
+	returns := []float64{}
+	first, column := true, -1
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ';' || r == '\t' || r == ' ' })
+		if len(fields) == 0 {
+			continue // Only separators, like the empty rows of a spreadsheet export
+		}
+		value := fields[len(fields)-1]
+		if column >= 0 && column < len(fields) {
+			value = fields[column]
+		}
+		annualReturn, err := parsePercent(value)
+		if err != nil {
+			if first {
+				first, column = false, columnIndex(fields, "return", "")
+				continue // Header
+			}
+			return nil, fmt.Errorf("line %d: invalid return %q", line, value)
+		}
+		first = false
+		if annualReturn < -100 {
+			return nil, fmt.Errorf("line %d: return %g%% is below -100%%", line, annualReturn)
+		}
+		returns = append(returns, annualReturn)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(returns) == 0 {
+		return nil, fmt.Errorf("no returns found")
+	}
+	return returns, nil
+}
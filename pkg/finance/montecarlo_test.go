@@ -0,0 +1,227 @@
+package finance
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSimulateInvestment(t *testing.T) {
+	input := InvestmentInput{
+		Principal:    NewMoney(10000),
+		AnnualYield:  7,
+		TaxRate:      26,
+		Inflation:    2,
+		Years:        20,
+		Contribution: NewMoney(200),
+	}
+
+	t.Run("No volatility", func(t *testing.T) {
+		expected, err := CalculateInvestment(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		result, err := SimulateInvestment(input, SimulationInput{Simulations: 10})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for name, value := range map[string]Money{"ConstantYield": result.ConstantYield, "P5": result.P5, "P50": result.P50, "P95": result.P95, "Mean": result.Mean} {
+			if value != expected.NetFutureValue {
+				t.Errorf("%s = %v, want %v", name, value, expected.NetFutureValue)
+			}
+		}
+	})
+
+	t.Run("Reproducible with a seed", func(t *testing.T) {
+		simulation := SimulationInput{Simulations: 500, Seed: 42, Volatility: 15, Workers: 1}
+		first, err := SimulateInvestment(input, simulation)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// The outcomes do not depend on the number of workers
+		simulation.Workers = 8
+		second, err := SimulateInvestment(input, simulation)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if first != second {
+			t.Errorf("Same seed gave %+v and %+v", first, second)
+		}
+
+		simulation.Seed = 43
+		other, err := SimulateInvestment(input, simulation)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if other.P50 == first.P50 {
+			t.Errorf("Different seeds gave the same median %v", other.P50)
+		}
+	})
+
+	for _, distribution := range []ReturnDistribution{NormalReturns, LogNormalReturns} {
+		t.Run(string(distribution), func(t *testing.T) {
+			result, err := SimulateInvestment(input, SimulationInput{Simulations: 2000, Seed: 1, Distribution: distribution, Volatility: 15})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			percentiles := []Money{result.P5, result.P25, result.P50, result.P75, result.P95}
+			if !slices.IsSortedFunc(percentiles, Money.Cmp) || result.P5 == result.P95 {
+				t.Errorf("Percentiles %v are not increasing", percentiles)
+			}
+			// Volatility drags the median below the outcome of the constant yield
+			if result.P50.Cmp(result.ConstantYield) >= 0 {
+				t.Errorf("P50 = %v, want below the constant yield outcome %v", result.P50, result.ConstantYield)
+			}
+			if result.Distribution != distribution || result.Simulations != 2000 {
+				t.Errorf("Distribution = %s with %d runs, want %s with 2000", result.Distribution, result.Simulations, distribution)
+			}
+		})
+	}
+
+	t.Run("Log-normal mean", func(t *testing.T) {
+		lumpSum := InvestmentInput{Principal: NewMoney(1000), AnnualYield: 8, Years: 1}
+		result, err := SimulateInvestment(lumpSum, SimulationInput{Simulations: 20000, Seed: 7, Distribution: LogNormalReturns, Volatility: 20})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !approximatelyEqual(result.Mean.Float64(), 1080, 0.01) {
+			t.Errorf("Mean = %v, want about 1080.00", result.Mean)
+		}
+	})
+}
+
+func TestSimulateBootstrap(t *testing.T) {
+	input := InvestmentInput{Principal: NewMoney(1000), Years: 1}
+
+	tests := []struct {
+		name                string
+		history             []float64
+		target              float64
+		expectedProbability float64
+		tolerance           float64
+	}{
+		{name: "Single historical return", history: []float64{10}, target: 1100, expectedProbability: 100},
+		{name: "Target out of reach", history: []float64{10}, target: 1100.01, expectedProbability: 0},
+		{name: "Half the years reach the target", history: []float64{-10, 30}, target: 1000, expectedProbability: 50, tolerance: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := SimulateInvestment(input, SimulationInput{
+				Simulations:  4000,
+				Seed:         3,
+				Distribution: BootstrapReturns,
+				History:      tt.history,
+				Target:       NewMoney(tt.target),
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if diff := result.TargetProbability - tt.expectedProbability; diff > tt.tolerance || diff < -tt.tolerance {
+				t.Errorf("TargetProbability = %.2f%%, want %.0f%%", result.TargetProbability, tt.expectedProbability)
+			}
+			if result.P5.Cmp(NewMoney(900)) < 0 || result.P95.Cmp(NewMoney(1300)) > 0 {
+				t.Errorf("Percentiles %v to %v are outside the historical outcomes", result.P5, result.P95)
+			}
+		})
+	}
+}
+
+func TestSimulateRetirement(t *testing.T) {
+	input := RetirementInput{
+		CurrentAge:          40,
+		RetirementAge:       65,
+		CurrentSavings:      NewMoney(50000),
+		MonthlyContribution: NewMoney(500),
+		WithdrawalRate:      4,
+		AnnualYield:         6,
+		Inflation:           2,
+	}
+
+	expected, err := CalculateRetirement(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	result, err := SimulateRetirement(input, SimulationInput{Simulations: 5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !approximatelyEqual(result.P50.Float64(), expected.RetirementSavings.Float64(), 0.0001) || result.ConstantYield != expected.RetirementSavings {
+		t.Errorf("P50 = %v, ConstantYield = %v, want %v without volatility", result.P50, result.ConstantYield, expected.RetirementSavings)
+	}
+
+	result, err = SimulateRetirement(input, SimulationInput{Simulations: 1000, Seed: 9, Volatility: 12, Target: expected.RetirementSavings})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.TargetProbability <= 0 || result.TargetProbability >= 60 {
+		t.Errorf("TargetProbability = %.2f%%, want below the even odds of the constant yield", result.TargetProbability)
+	}
+}
+
+func TestSimulationValidation(t *testing.T) {
+	input := InvestmentInput{Principal: NewMoney(1000), AnnualYield: 5, Years: 10}
+
+	invalid := map[string]SimulationInput{
+		"No simulations":         {},
+		"Too many simulations":   {Simulations: MaxSimulations + 1},
+		"Negative workers":       {Simulations: 10, Workers: -1},
+		"Negative volatility":    {Simulations: 10, Volatility: -5},
+		"Negative target":        {Simulations: 10, Target: NewMoney(-1)},
+		"Unknown distribution":   {Simulations: 10, Distribution: "uniform"},
+		"Bootstrap without data": {Simulations: 10, Distribution: BootstrapReturns},
+		"Return below -100%":     {Simulations: 10, Distribution: BootstrapReturns, History: []float64{5, -120}},
+	}
+	for name, simulation := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := SimulateInvestment(input, simulation)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected a ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseReturns(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected []float64
+		wantErr  bool
+	}{
+		{name: "One return per line", data: "7.5\n-12%\n\n20\n", expected: []float64{7.5, -12, 20}},
+		{name: "Years and header", data: "# S&P 500\nyear,return\n2007,5.5\n2008,-37.0\n2009;26.5\n", expected: []float64{5.5, -37, 26.5}},
+		{name: "Return column of a backtest file", data: "year,return,inflation\n2007,5.5,2.1\n2008,-37,3.3\n", expected: []float64{5.5, -37}},
+		{name: "Lines of bare separators", data: ",\n5\n;;\n\t,\n-3\n", expected: []float64{5, -3}},
+		{name: "Invalid return", data: "5\nten\n", wantErr: true},
+		{name: "Below -100%", data: "5\n-101\n", wantErr: true},
+		{name: "Not a number", data: "5\nNaN\n", wantErr: true},
+		{name: "Infinite", data: "5\n+Inf%\n", wantErr: true},
+		{name: "Empty", data: "# no data\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			returns, err := ParseReturns(strings.NewReader(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %v", returns)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !slices.Equal(returns, tt.expected) {
+				t.Errorf("ParseReturns() = %v, want %v", returns, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,461 @@
+package finance
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+)
+
+// DefaultRebalanceBand is the drift in percentage points that triggers a threshold rebalance
+const DefaultRebalanceBand = 5.0
+
+// RebalancePolicy selects when a portfolio is brought back to its target weights
+type RebalancePolicy string
+
+const (
+	NeverRebalance     RebalancePolicy = "never"
+	CalendarRebalance  RebalancePolicy = "calendar"  // Every RebalanceMonths months
+	ThresholdRebalance RebalancePolicy = "threshold" // When a weight drifts outside the band
+)
+
+// Asset is an asset class of a portfolio
+type Asset struct {
+	Name           string
+	Weight         float64 // Target weight in percent
+	ExpectedReturn float64 // Expected annual return in percent
+	Volatility     float64 // Standard deviation of the annual return in percent
+}
+
+// PortfolioInput represents the input parameters for a portfolio projection. The
+// returns of each month are drawn with the expected returns, the volatilities and the
+// correlations of the assets; without volatility every month returns the expectation.
+type PortfolioInput struct {
+	Principal       Money
+	Assets          []Asset
+	Correlations    [][]float64 // Correlation matrix in the order of the assets (default: uncorrelated)
+	Years           int
+	Policy          RebalancePolicy // Default: NeverRebalance
+	RebalanceMonths int             // Interval of CalendarRebalance (default: 12)
+	Band            float64         // Drift in percentage points of ThresholdRebalance, above 0, such as DefaultRebalanceBand
+	TaxRate         float64         // Tax on the gains realized by selling
+	Seed            uint64          // Projections with the same seed draw the same returns
+}
+
+// PortfolioAsset is the final state of an asset
+type PortfolioAsset struct {
+	Name   string  `json:"name"`
+	Target float64 `json:"target"` // Target weight in percent
+	Weight float64 `json:"weight"` // Final weight in percent
+	Value  Money   `json:"value"`
+	Basis  Money   `json:"basis"` // Cost basis of the holding
+}
+
+// PortfolioDrift is the weight of an asset at the end of a year, before any rebalancing
+type PortfolioDrift struct {
+	Year   int     `json:"year"`
+	Asset  string  `json:"asset"`
+	Value  Money   `json:"value"`
+	Weight float64 `json:"weight"` // In percent
+	Drift  float64 `json:"drift"`  // Weight minus target, in percentage points
+}
+
+// RebalanceTrade is a purchase or a sale of a rebalancing
+type RebalanceTrade struct {
+	Month  int    `json:"month"`
+	Asset  string `json:"asset"`
+	Amount Money  `json:"amount"` // Positive for a purchase, negative for a sale
+	Gain   Money  `json:"gain"`   // Realized by a sale
+	Tax    Money  `json:"tax"`
+}
+
+// PortfolioResult represents the output of a portfolio projection
+type PortfolioResult struct {
+	Principal  Money            `json:"principal"`
+	Years      int              `json:"years"`
+	Policy     RebalancePolicy  `json:"policy"`
+	Seed       uint64           `json:"seed"`
+	FinalValue Money            `json:"final_value"`
+	Rebalances int              `json:"rebalances"`
+	Turnover   Money            `json:"turnover"` // Total sold by rebalancing
+	TaxPaid    Money            `json:"tax_paid"` // On gains realized by rebalancing
+	Assets     []PortfolioAsset `json:"assets"`
+	Drift      []PortfolioDrift `json:"drift"`
+	Trades     []RebalanceTrade `json:"trades"`
+}
+
+// Validate checks the assets, the correlations and the rebalancing policy
+func (input PortfolioInput) Validate() error {
+	if input.Principal.IsNegative() {
+		return invalidInput("Principal", "initial amount must not be negative")
+	}
+	if input.Years < 0 {
+		return invalidInput("Years", "investment duration must not be negative")
+	}
+	if err := validatePercentage("TaxRate", input.TaxRate, 0, 100); err != nil {
+		return err
+	}
+
+	if len(input.Assets) == 0 {
+		return invalidInput("Assets", "a portfolio needs at least one asset")
+	}
+	seen := map[string]bool{}
+	total := 0.0
+	for _, asset := range input.Assets {
+		name := strings.TrimSpace(asset.Name)
+		if name == "" {
+			return invalidInput("Assets", "assets need a name")
+		}
+		if seen[strings.ToLower(name)] {
+			return invalidInput("Assets", "duplicate asset: %s", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		if asset.Weight < 0 {
+			return invalidInput("Weight", "the weight of %s must not be negative", name)
+		}
+		if asset.ExpectedReturn <= -100 {
+			return invalidInput("ExpectedReturn", "the expected return of %s must be greater than -100%%", name)
+		}
+		if asset.Volatility < 0 {
+			return invalidInput("Volatility", "the volatility of %s must not be negative", name)
+		}
+		total += asset.Weight
+	}
+	if math.Abs(total-100) > 0.01 {
+		return invalidInput("Weight", "target weights add up to %g%%, not 100%%", total)
+	}
+
+	if input.Correlations != nil {
+		if _, err := choleskyFactor(input.Correlations, len(input.Assets)); err != nil {
+			return invalidInput("Correlations", "%s", err)
+		}
+	}
+
+	switch input.Policy {
+	case "", NeverRebalance, CalendarRebalance, ThresholdRebalance:
+	default:
+		return invalidInput("Policy", "unknown rebalancing policy: %s (expected never, calendar or threshold)", input.Policy)
+	}
+	if input.RebalanceMonths < 0 {
+		return invalidInput("RebalanceMonths", "rebalancing interval must not be negative")
+	}
+	if input.Band < 0 || input.Band > 100 {
+		return invalidInput("Band", "rebalancing band must be between 0 and 100 percentage points")
+	}
+	if input.Policy == ThresholdRebalance && input.Band == 0 {
+		return invalidInput("Band", "threshold rebalancing needs a band above 0; rebalance every month with the calendar policy instead")
+	}
+	return nil
+}
+
+// ProjectPortfolio grows the assets month by month and rebalances them with the
+// policy. Sales realize the gain over the average cost basis, and the tax on it is
+// paid out of the portfolio before the purchases.
+func ProjectPortfolio(input PortfolioInput) (PortfolioResult, error) {
+	if err := input.Validate(); err != nil {
+		return PortfolioResult{}, err
+	}
+
+	n := len(input.Assets)
+	correlations := input.Correlations
+	if correlations == nil {
+		correlations = identityMatrix(n)
+	}
+	factor, _ := choleskyFactor(correlations, n)
+
+	policy := input.Policy
+	if policy == "" {
+		policy = NeverRebalance
+	}
+	interval := input.RebalanceMonths
+	if interval == 0 {
+		interval = 12
+	}
+
+	targets := make([]float64, n)
+	meanRates := make([]float64, n)
+	monthlyVolatility := make([]float64, n)
+	for i, asset := range input.Assets {
+		targets[i] = asset.Weight
+		meanRates[i] = math.Pow(1+asset.ExpectedReturn/100, 1.0/12) - 1
+		monthlyVolatility[i] = asset.Volatility / 100 / math.Sqrt(12)
+	}
+
+	// Holdings are kept unrounded so that rounding does not compound over the months
+	values := make([]float64, n)
+	basis := make([]float64, n)
+	for i, amount := range input.Principal.Allocate(targets) {
+		values[i], basis[i] = amount.Float64(), amount.Float64()
+	}
+
+	result := PortfolioResult{
+		Principal: input.Principal,
+		Years:     input.Years,
+		Policy:    policy,
+		Seed:      input.Seed,
+		Assets:    []PortfolioAsset{},
+		Drift:     []PortfolioDrift{},
+		Trades:    []RebalanceTrade{},
+	}
+
+	rng := rand.New(rand.NewPCG(input.Seed, 0))
+	shocks := make([]float64, n)
+	for month := 1; month <= input.Years*12; month++ {
+		for i := range shocks {
+			shocks[i] = rng.NormFloat64()
+		}
+		for i := range values {
+			// Correlated shock: row i of the Cholesky factor times the independent draws
+			shock := 0.0
+			for j := 0; j <= i; j++ {
+				shock += factor[i][j] * shocks[j]
+			}
+			values[i] *= max(1+meanRates[i]+monthlyVolatility[i]*shock, 0)
+		}
+
+		weights := portfolioWeights(values)
+		if month%12 == 0 {
+			for i, asset := range input.Assets {
+				result.Drift = append(result.Drift, PortfolioDrift{
+					Year:   month / 12,
+					Asset:  strings.TrimSpace(asset.Name),
+					Value:  NewMoney(values[i]).RoundCents(),
+					Weight: weights[i],
+					Drift:  weights[i] - targets[i],
+				})
+			}
+		}
+
+		rebalance := false
+		switch policy {
+		case CalendarRebalance:
+			rebalance = month%interval == 0
+		case ThresholdRebalance:
+			for i := range weights {
+				rebalance = rebalance || math.Abs(weights[i]-targets[i]) > input.Band
+			}
+		}
+		if rebalance {
+			trades := rebalancePortfolio(values, basis, targets, input.TaxRate/100)
+			for i, trade := range trades {
+				if trade.Amount.IsZero() {
+					continue
+				}
+				trade.Month = month
+				trade.Asset = strings.TrimSpace(input.Assets[i].Name)
+				result.Trades = append(result.Trades, trade)
+				result.TaxPaid = result.TaxPaid.Add(trade.Tax)
+				if trade.Amount.IsNegative() {
+					result.Turnover = result.Turnover.Sub(trade.Amount)
+				}
+			}
+			result.Rebalances++
+		}
+	}
+
+	weights := portfolioWeights(values)
+	total := 0.0
+	for i, asset := range input.Assets {
+		result.Assets = append(result.Assets, PortfolioAsset{
+			Name:   strings.TrimSpace(asset.Name),
+			Target: targets[i],
+			Weight: weights[i],
+			Value:  NewMoney(values[i]).RoundCents(),
+			Basis:  NewMoney(basis[i]).RoundCents(),
+		})
+		total += values[i]
+	}
+	result.FinalValue = NewMoney(total).RoundCents()
+
+	return result, nil
+}
+
+// rebalancePortfolio trades the holdings back to the target weights. The tax on the
+// gains of the sales lowers the amount to rebalance, which in turn changes the sales,
+// so the amount is found by iterating until it is stable to the cent.
+func rebalancePortfolio(values, basis, targets []float64, taxRate float64) []RebalanceTrade {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+
+	tax, amounts, gains := 0.0, make([]float64, len(values)), make([]float64, len(values))
+	for range 50 {
+		net := total - tax
+		next := 0.0
+		for i, value := range values {
+			amounts[i] = net*targets[i]/100 - value
+			gains[i] = 0
+			if amounts[i] < 0 && value > 0 {
+				gains[i] = max(-amounts[i]*(1-basis[i]/value), 0)
+				next += gains[i] * taxRate
+			}
+		}
+		if math.Abs(next-tax) < 0.005 {
+			break
+		}
+		tax = next
+	}
+
+	trades := make([]RebalanceTrade, len(values))
+	for i, value := range values {
+		if amounts[i] < 0 && value > 0 {
+			basis[i] *= 1 + amounts[i]/value // The sale takes its share of the cost basis
+		} else {
+			basis[i] += amounts[i]
+		}
+		values[i] += amounts[i]
+		trades[i] = RebalanceTrade{
+			Amount: NewMoney(amounts[i]).RoundCents(),
+			Gain:   NewMoney(gains[i]).RoundCents(),
+			Tax:    NewMoney(gains[i] * taxRate).RoundCents(),
+		}
+	}
+	return trades
+}
+
+// portfolioWeights returns the weight of each value in percent
+func portfolioWeights(values []float64) []float64 {
+	total := 0.0
+	for _, value := range values {
+		total += value
+	}
+	weights := make([]float64, len(values))
+	if total > 0 {
+		for i, value := range values {
+			weights[i] = value / total * 100
+		}
+	}
+	return weights
+}
+
+func identityMatrix(n int) [][]float64 {
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+		matrix[i][i] = 1
+	}
+	return matrix
+}
+
+// choleskyFactor returns the lower triangular L with L·Lᵀ equal to the correlation
+// matrix. Positive semidefinite matrices, such as perfectly correlated assets, are
+// accepted: a zero pivot leaves the rest of its column at zero.
+func choleskyFactor(matrix [][]float64, n int) ([][]float64, error) {
+	const tolerance = 1e-9
+
+	if len(matrix) != n {
+		return nil, fmt.Errorf("correlation matrix has %d rows for %d assets", len(matrix), n)
+	}
+	for i, row := range matrix {
+		if len(row) != n {
+			return nil, fmt.Errorf("row %d of the correlation matrix has %d columns for %d assets", i+1, len(row), n)
+		}
+	}
+	for i, row := range matrix {
+		if math.Abs(row[i]-1) > tolerance {
+			return nil, fmt.Errorf("the correlation of asset %d with itself must be 1", i+1)
+		}
+		for j, value := range row {
+			if value < -1 || value > 1 {
+				return nil, fmt.Errorf("correlation %g is outside [-1, 1]", value)
+			}
+			if math.Abs(value-matrix[j][i]) > tolerance {
+				return nil, fmt.Errorf("the correlation matrix is not symmetric")
+			}
+		}
+	}
+
+	factor := identityMatrix(n)
+	for i := range n {
+		for j := 0; j <= i; j++ {
+			sum := matrix[i][j]
+			for k := 0; k < j; k++ {
+				sum -= factor[i][k] * factor[j][k]
+			}
+
+			if i == j {
+				if sum < -tolerance {
+					return nil, fmt.Errorf("the correlations are inconsistent: the matrix is not positive semidefinite")
+				}
+				factor[i][i] = math.Sqrt(max(sum, 0))
+			} else if factor[j][j] > tolerance {
+				factor[i][j] = sum / factor[j][j]
+			} else if math.Abs(sum) > tolerance {
+				return nil, fmt.Errorf("the correlations are inconsistent: the matrix is not positive semidefinite")
+			} else {
+				factor[i][j] = 0
+			}
+		}
+	}
+	return factor, nil
+}
+
+// AssetCorrelation is the correlation between two assets named in a portfolio
+type AssetCorrelation struct {
+	First  string
+	Second string
+	Value  float64
+}
+
+// CorrelationMatrix builds the correlation matrix of the assets from pairwise
+// correlations; pairs that are not given are uncorrelated
+func CorrelationMatrix(assets []Asset, pairs []AssetCorrelation) ([][]float64, error) {
+	index := map[string]int{}
+	for i, asset := range assets {
+		index[strings.ToLower(strings.TrimSpace(asset.Name))] = i
+	}
+
+	matrix := identityMatrix(len(assets))
+	for _, pair := range pairs {
+		i, foundFirst := index[strings.ToLower(strings.TrimSpace(pair.First))]
+		j, foundSecond := index[strings.ToLower(strings.TrimSpace(pair.Second))]
+		if !foundFirst || !foundSecond {
+			return nil, fmt.Errorf("correlation %s,%s names an asset that is not in the portfolio", pair.First, pair.Second)
+		}
+		if i == j {
+			return nil, fmt.Errorf("correlation %s,%s needs two different assets", pair.First, pair.Second)
+		}
+		matrix[i][j], matrix[j][i] = pair.Value, pair.Value
+	}
+	return matrix, nil
+}
+
+// ParseAsset reads an asset in the form name=weight:return:volatility, all in percent
+func ParseAsset(value string) (Asset, error) {
+	name, definition, found := strings.Cut(value, "=")
+	fields := strings.Split(definition, ":")
+	if !found || strings.TrimSpace(name) == "" || len(fields) != 3 {
+		return Asset{}, fmt.Errorf("invalid asset %q, expected name=weight:return:volatility", value)
+	}
+
+	asset := Asset{Name: strings.TrimSpace(name)}
+	var err error
+	if asset.Weight, err = parsePercent(fields[0]); err != nil {
+		return Asset{}, fmt.Errorf("asset %s: invalid weight %q", asset.Name, fields[0])
+	}
+	if asset.ExpectedReturn, err = parsePercent(fields[1]); err != nil {
+		return Asset{}, fmt.Errorf("asset %s: invalid return %q", asset.Name, fields[1])
+	}
+	if asset.Volatility, err = parsePercent(fields[2]); err != nil {
+		return Asset{}, fmt.Errorf("asset %s: invalid volatility %q", asset.Name, fields[2])
+	}
+	return asset, nil
+}
+
+// ParseCorrelation reads the correlation of two assets in the form first,second=value
+func ParseCorrelation(value string) (AssetCorrelation, error) {
+	names, number, found := strings.Cut(value, "=")
+	first, second, paired := strings.Cut(names, ",")
+	if !found || !paired || strings.TrimSpace(first) == "" || strings.TrimSpace(second) == "" {
+		return AssetCorrelation{}, fmt.Errorf("invalid correlation %q, expected first,second=value", value)
+	}
+
+	correlation, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil {
+		return AssetCorrelation{}, fmt.Errorf("invalid correlation %q between %s and %s", number, first, second)
+	}
+	return AssetCorrelation{First: strings.TrimSpace(first), Second: strings.TrimSpace(second), Value: correlation}, nil
+}
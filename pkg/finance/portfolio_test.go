@@ -0,0 +1,286 @@
+package finance
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+// stocksAndBonds returns a 60/40 portfolio of assets without volatility
+func stocksAndBonds() []Asset {
+	return []Asset{
+		{Name: "Stocks", Weight: 60, ExpectedReturn: 10},
+		{Name: "Bonds", Weight: 40, ExpectedReturn: 0},
+	}
+}
+
+func TestProjectPortfolio(t *testing.T) {
+	tests := []struct {
+		name               string
+		input              PortfolioInput
+		expectedValue      float64
+		expectedRebalances int
+		expectedTax        float64
+		expectedTrades     int
+	}{
+		{
+			name:               "Never rebalanced",
+			input:              PortfolioInput{Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 2},
+			expectedValue:      1126, // 600 * 1.1^2 + 400
+			expectedRebalances: 0,
+		},
+		{
+			name:               "Yearly rebalancing",
+			input:              PortfolioInput{Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 2, Policy: CalendarRebalance},
+			expectedValue:      1123.60, // 636 * 1.1 + 424, rebalanced to 60/40 after each year
+			expectedRebalances: 2,
+			expectedTrades:     4,
+		},
+		{
+			name:               "Quarterly rebalancing",
+			input:              PortfolioInput{Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Policy: CalendarRebalance, RebalanceMonths: 3},
+			expectedRebalances: 4,
+			expectedTrades:     8,
+		},
+		{
+			name:               "Drift inside the band",
+			input:              PortfolioInput{Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Policy: ThresholdRebalance, Band: DefaultRebalanceBand},
+			expectedValue:      1060,
+			expectedRebalances: 0,
+		},
+		{
+			name:               "Drift outside the band",
+			input:              PortfolioInput{Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Policy: ThresholdRebalance, Band: 1},
+			expectedRebalances: 2, // Stocks pass 61% in the sixth month after each rebalancing
+			expectedTrades:     4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ProjectPortfolio(tt.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.expectedValue != 0 && result.FinalValue != NewMoney(tt.expectedValue) {
+				t.Errorf("FinalValue = %v, want %.2f", result.FinalValue, tt.expectedValue)
+			}
+			if result.Rebalances != tt.expectedRebalances || len(result.Trades) != tt.expectedTrades {
+				t.Errorf("Got %d rebalances and %d trades, want %d and %d", result.Rebalances, len(result.Trades), tt.expectedRebalances, tt.expectedTrades)
+			}
+			if len(result.Drift) != tt.input.Years*2 {
+				t.Errorf("Got %d drift rows, want %d", len(result.Drift), tt.input.Years*2)
+			}
+		})
+	}
+}
+
+func TestPortfolioDrift(t *testing.T) {
+	result, err := ProjectPortfolio(PortfolioInput{Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Policy: CalendarRebalance})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// At the end of the year, before rebalancing, stocks are 660 of 1060
+	drift := result.Drift[0]
+	if drift.Asset != "Stocks" || drift.Value != NewMoney(660) || !approximatelyEqual(drift.Weight, 62.2642, 0.0001) || !approximatelyEqual(drift.Drift, 2.2642, 0.0001) {
+		t.Errorf("Drift = %+v, want Stocks at 660.00, 62.26%%, +2.26 points", drift)
+	}
+
+	sale, purchase := result.Trades[0], result.Trades[1]
+	if sale.Month != 12 || sale.Asset != "Stocks" || sale.Amount != NewMoney(-24) || sale.Gain != NewMoney(2.18) {
+		t.Errorf("Sale = %+v, want 24.00 of stocks in month 12 with a 2.18 gain", sale)
+	}
+	if purchase.Asset != "Bonds" || purchase.Amount != NewMoney(24) || !purchase.Gain.IsZero() {
+		t.Errorf("Purchase = %+v, want 24.00 of bonds", purchase)
+	}
+	if result.Turnover != NewMoney(24) || !result.TaxPaid.IsZero() {
+		t.Errorf("Turnover = %v, TaxPaid = %v, want 24.00 and no tax", result.Turnover, result.TaxPaid)
+	}
+	if result.Assets[0].Weight != 60 || result.Assets[0].Value != NewMoney(636) {
+		t.Errorf("Final stocks = %+v, want 636.00 at 60%%", result.Assets[0])
+	}
+}
+
+func TestPortfolioRebalancingTax(t *testing.T) {
+	result, err := ProjectPortfolio(PortfolioInput{Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Policy: CalendarRebalance, TaxRate: 50})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The sale s realizes s/11 of gain over the 600 basis; its tax s/22 is paid before
+	// the purchase, so the portfolio T solves T = 1060 - (660 - 0.6T) / 22
+	total := 22660 / 21.4
+	sale := 660 - 0.6*total
+	if result.FinalValue != NewMoney(total).RoundCents() {
+		t.Errorf("FinalValue = %v, want %.2f", result.FinalValue, total)
+	}
+	if result.Trades[0].Amount != NewMoney(-sale).RoundCents() || result.TaxPaid != NewMoney(sale/22).RoundCents() {
+		t.Errorf("Sale = %v, TaxPaid = %v, want %.2f and %.2f", result.Trades[0].Amount, result.TaxPaid, -sale, sale/22)
+	}
+	if !approximatelyEqual(result.Assets[0].Weight, 60, 0.0001) {
+		t.Errorf("Stocks weight = %.4f%%, want 60%% after rebalancing", result.Assets[0].Weight)
+	}
+	// The cost basis keeps the unrealized gain of the stocks that were not sold
+	if result.Assets[0].Basis.Cmp(result.Assets[0].Value) >= 0 {
+		t.Errorf("Stocks basis = %v, want below the value %v", result.Assets[0].Basis, result.Assets[0].Value)
+	}
+}
+
+func TestPortfolioVolatility(t *testing.T) {
+	assets := []Asset{
+		{Name: "World", Weight: 50, ExpectedReturn: 7, Volatility: 15},
+		{Name: "Europe", Weight: 50, ExpectedReturn: 7, Volatility: 15},
+	}
+
+	t.Run("Reproducible with a seed", func(t *testing.T) {
+		input := PortfolioInput{Principal: NewMoney(10000), Assets: assets, Years: 10, Seed: 5}
+		first, err := ProjectPortfolio(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		second, err := ProjectPortfolio(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if first.FinalValue != second.FinalValue {
+			t.Errorf("Same seed gave %v and %v", first.FinalValue, second.FinalValue)
+		}
+		if first.Assets[0].Value == first.Assets[1].Value {
+			t.Errorf("Uncorrelated assets ended at the same value %v", first.Assets[0].Value)
+		}
+	})
+
+	t.Run("Perfectly correlated assets", func(t *testing.T) {
+		input := PortfolioInput{Principal: NewMoney(10000), Assets: assets, Correlations: [][]float64{{1, 1}, {1, 1}}, Years: 10, Seed: 5}
+		result, err := ProjectPortfolio(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		for _, drift := range result.Drift {
+			if math.Abs(drift.Drift) > 1e-9 {
+				t.Fatalf("Drift = %+v, want none for identical assets", drift)
+			}
+		}
+	})
+}
+
+func TestCholeskyFactor(t *testing.T) {
+	factor, err := choleskyFactor([][]float64{{1, 0.5}, {0.5, 1}}, 2)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := [][]float64{{1, 0}, {0.5, math.Sqrt(0.75)}}
+	for i := range expected {
+		for j := range expected[i] {
+			if math.Abs(factor[i][j]-expected[i][j]) > 1e-12 {
+				t.Errorf("L[%d][%d] = %v, want %v", i, j, factor[i][j], expected[i][j])
+			}
+		}
+	}
+
+	invalid := []struct {
+		name   string
+		matrix [][]float64
+		assets int
+	}{
+		{name: "Wrong size", matrix: [][]float64{{1}}, assets: 2},
+		{name: "Ragged", matrix: [][]float64{{1, 0}, {}}, assets: 2},
+		{name: "Not symmetric", matrix: [][]float64{{1, 0.5}, {0.2, 1}}, assets: 2},
+		{name: "Diagonal not 1", matrix: [][]float64{{1, 0}, {0, 0.9}}, assets: 2},
+		{name: "Out of range", matrix: [][]float64{{1, 1.5}, {1.5, 1}}, assets: 2},
+		{name: "Inconsistent", matrix: [][]float64{{1, 0.9, -0.9}, {0.9, 1, 0.9}, {-0.9, 0.9, 1}}, assets: 3},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := choleskyFactor(tt.matrix, tt.assets); err == nil {
+				t.Error("Expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestPortfolioValidation(t *testing.T) {
+	invalid := map[string]PortfolioInput{
+		"No assets":              {Principal: NewMoney(1000), Years: 1},
+		"Weights below 100":      {Principal: NewMoney(1000), Assets: []Asset{{Name: "Stocks", Weight: 90}}, Years: 1},
+		"Duplicate asset":        {Principal: NewMoney(1000), Assets: []Asset{{Name: "Stocks", Weight: 50}, {Name: "stocks", Weight: 50}}, Years: 1},
+		"Negative weight":        {Principal: NewMoney(1000), Assets: []Asset{{Name: "Stocks", Weight: 110}, {Name: "Cash", Weight: -10}}, Years: 1},
+		"Negative volatility":    {Principal: NewMoney(1000), Assets: []Asset{{Name: "Stocks", Weight: 100, Volatility: -1}}, Years: 1},
+		"Unknown policy":         {Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Policy: "monthly"},
+		"Band above 100":         {Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Band: 150},
+		"Threshold without band": {Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Policy: ThresholdRebalance},
+		"Ragged correlations":    {Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Correlations: [][]float64{{1, 0}, {}}},
+		"Correlation size":       {Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, Correlations: [][]float64{{1}}},
+		"Tax above 100%":         {Principal: NewMoney(1000), Assets: stocksAndBonds(), Years: 1, TaxRate: 120},
+	}
+	for name, input := range invalid {
+		t.Run(name, func(t *testing.T) {
+			_, err := ProjectPortfolio(input)
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Errorf("Expected a ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestParseAsset(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected Asset
+		wantErr  bool
+	}{
+		{value: "Stocks=60:7:15", expected: Asset{Name: "Stocks", Weight: 60, ExpectedReturn: 7, Volatility: 15}},
+		{value: " Gold = 10%:2.5%:18% ", expected: Asset{Name: "Gold", Weight: 10, ExpectedReturn: 2.5, Volatility: 18}},
+		{value: "Stocks=60:7", wantErr: true},
+		{value: "=60:7:15", wantErr: true},
+		{value: "Stocks=sixty:7:15", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			asset, err := ParseAsset(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", asset)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if asset != tt.expected {
+				t.Errorf("ParseAsset() = %+v, want %+v", asset, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCorrelationMatrix(t *testing.T) {
+	assets := []Asset{{Name: "Stocks"}, {Name: "Bonds"}, {Name: "Gold"}}
+
+	correlation, err := ParseCorrelation("stocks, Gold=0.3")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	matrix, err := CorrelationMatrix(assets, []AssetCorrelation{correlation})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if matrix[0][2] != 0.3 || matrix[2][0] != 0.3 || matrix[0][1] != 0 || matrix[1][1] != 1 {
+		t.Errorf("CorrelationMatrix() = %v, want 0.3 between stocks and gold only", matrix)
+	}
+
+	for _, value := range []string{"Stocks=0.3", "Stocks,Bonds", "Stocks,Bonds=high"} {
+		if _, err := ParseCorrelation(value); err == nil {
+			t.Errorf("ParseCorrelation(%q): expected an error", value)
+		}
+	}
+	for _, pair := range []AssetCorrelation{{First: "Stocks", Second: "Cash"}, {First: "Gold", Second: "gold"}} {
+		if _, err := CorrelationMatrix(assets, []AssetCorrelation{pair}); err == nil {
+			t.Errorf("CorrelationMatrix(%+v): expected an error", pair)
+		}
+	}
+}
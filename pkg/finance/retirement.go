@@ -0,0 +1,105 @@
+package finance
+
+import (
+	"math"
+)
+
+// RetirementInput represents the input parameters for retirement calculation
+type RetirementInput struct {
+	CurrentAge          int
+	RetirementAge       int
+	CurrentSavings      Money
+	MonthlyContribution Money
+	WithdrawalRate      float64
+	AnnualYield         float64
+	Inflation           float64
+}
+
+// RetirementResult represents the output of retirement calculation
+type RetirementResult struct {
+	CurrentAge            int   `json:"current_age"`
+	RetirementAge         int   `json:"retirement_age"`
+	YearsToRetirement     int   `json:"years_to_retirement"`
+	RetirementSavings     Money `json:"retirement_savings"`
+	AnnualWithdrawal      Money `json:"annual_withdrawal"`
+	MonthlyWithdrawal     Money `json:"monthly_withdrawal"`
+	RealMonthlyWithdrawal Money `json:"real_monthly_withdrawal"`
+}
+
+// Validate checks that the retirement parameters are within range
+func (input RetirementInput) Validate() error {
+	if input.CurrentAge < 0 {
+		return invalidInput("CurrentAge", "current age must not be negative")
+	}
+	if input.RetirementAge < input.CurrentAge {
+		return invalidInput("RetirementAge", "retirement age must not be below the current age")
+	}
+	if input.CurrentSavings.IsNegative() {
+		return invalidInput("CurrentSavings", "current savings must not be negative")
+	}
+	if input.MonthlyContribution.IsNegative() {
+		return invalidInput("MonthlyContribution", "monthly contribution must not be negative")
+	}
+	if err := validatePercentage("WithdrawalRate", input.WithdrawalRate, 0, 100); err != nil {
+		return err
+	}
+	if input.AnnualYield <= -100 {
+		return invalidInput("AnnualYield", "annual yield must be greater than -100%%")
+	}
+	if input.Inflation <= -100 {
+		return invalidInput("Inflation", "inflation must be greater than -100%%")
+	}
+	return nil
+}
+
+// CalculateRetirement projects the savings at retirement and the sustainable withdrawals
+func CalculateRetirement(input RetirementInput) (RetirementResult, error) {
+	if err := input.Validate(); err != nil {
+		return RetirementResult{}, err
+	}
+
+	yearsToRetirement := input.RetirementAge - input.CurrentAge
+	monthsToRetirement := yearsToRetirement * 12
+
+	// Calculate retirement savings at retirement age
+	monthlyRate := input.AnnualYield / 100 / 12
+
+	// Future value calculation
+	retirementSavings := input.CurrentSavings.Mul(math.Pow(1+monthlyRate, float64(monthsToRetirement)))
+	if monthlyRate != 0 {
+		retirementSavings = retirementSavings.Add(input.MonthlyContribution.Mul((math.Pow(1+monthlyRate, float64(monthsToRetirement)) - 1) / monthlyRate))
+	} else {
+		retirementSavings = retirementSavings.Add(input.MonthlyContribution.Mul(float64(monthsToRetirement)))
+	}
+	retirementSavings = retirementSavings.RoundCents()
+
+	// Calculate annual withdrawal amount
+	annualWithdrawal := retirementSavings.Mul(input.WithdrawalRate / 100).RoundCents()
+	monthlyWithdrawal := annualWithdrawal.Mul(1.0 / 12).RoundCents()
+
+	// Adjust for inflation
+	realMonthlyWithdrawal := monthlyWithdrawal.Mul(1 / math.Pow(1+input.Inflation/100, float64(yearsToRetirement))).RoundCents()
+
+	return RetirementResult{
+		CurrentAge:            input.CurrentAge,
+		RetirementAge:         input.RetirementAge,
+		YearsToRetirement:     yearsToRetirement,
+		RetirementSavings:     retirementSavings,
+		AnnualWithdrawal:      annualWithdrawal,
+		MonthlyWithdrawal:     monthlyWithdrawal,
+		RealMonthlyWithdrawal: realMonthlyWithdrawal,
+	}, nil
+}
+
+// retirementSavings accumulates the savings until retirement month by month with the
+// annual yield of each year in percent, as given by yield
+func retirementSavings(input RetirementInput, yield func(year int) float64) Money {
+	balance := input.CurrentSavings.Float64()
+	for year := 1; year <= input.RetirementAge-input.CurrentAge; year++ {
+		monthlyRate := yield(year) / 100 / 12
+		for range 12 {
+			balance = balance*(1+monthlyRate) + input.MonthlyContribution.Float64()
+		}
+	}
+	return NewMoney(balance).RoundCents()
+}
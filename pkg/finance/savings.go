@@ -0,0 +1,86 @@
+package finance
+
+import (
+	"math"
+)
+
+// SavingsInput represents the input parameters for savings calculation
+type SavingsInput struct {
+	Initial        Money
+	MonthlyDeposit Money
+	AnnualYield    float64
+	Years          int
+	Inflation      float64
+}
+
+// SavingsResult represents the output of savings calculation
+type SavingsResult struct {
+	Initial         Money `json:"initial"`
+	MonthlyDeposit  Money `json:"monthly_deposit"`
+	FutureValue     Money `json:"future_value"`
+	RealFutureValue Money `json:"real_future_value"`
+	TotalDeposits   Money `json:"total_deposits"`
+	InterestEarned  Money `json:"interest_earned"`
+	Years           int   `json:"years"`
+	NumberOfMonths  int   `json:"number_of_months"`
+}
+
+// Validate checks that the savings parameters are within range
+func (input SavingsInput) Validate() error {
+	if input.Initial.IsNegative() {
+		return invalidInput("Initial", "initial deposit must not be negative")
+	}
+	if input.MonthlyDeposit.IsNegative() {
+		return invalidInput("MonthlyDeposit", "monthly deposit must not be negative")
+	}
+	if input.AnnualYield <= -100 {
+		return invalidInput("AnnualYield", "annual yield must be greater than -100%%")
+	}
+	if input.Inflation <= -100 {
+		return invalidInput("Inflation", "inflation must be greater than -100%%")
+	}
+	if input.Years < 0 {
+		return invalidInput("Years", "savings duration must not be negative")
+	}
+	return nil
+}
+
+// CalculateSavings computes the balance of a savings plan with monthly deposits
+func CalculateSavings(input SavingsInput) (SavingsResult, error) {
+	if err := input.Validate(); err != nil {
+		return SavingsResult{}, err
+	}
+
+	monthlyRate := input.AnnualYield / 100 / 12
+	numberOfMonths := input.Years * 12
+
+	// Calculate future value with regular deposits
+	// FV = P(1+r)^n + PMT * ((1+r)^n - 1) / r
+	futureValue := input.Initial.Mul(math.Pow(1+monthlyRate, float64(numberOfMonths)))
+	if monthlyRate != 0 {
+		futureValue = futureValue.Add(input.MonthlyDeposit.Mul((math.Pow(1+monthlyRate, float64(numberOfMonths)) - 1) / monthlyRate))
+	} else {
+		futureValue = futureValue.Add(input.MonthlyDeposit.Mul(float64(numberOfMonths)))
+	}
+	futureValue = futureValue.RoundCents()
+
+	totalDeposits := input.Initial.Add(input.MonthlyDeposit.Mul(float64(numberOfMonths)))
+	interestEarned := futureValue.Sub(totalDeposits)
+
+	// Adjust future value for inflation
+	realFutureValue := futureValue
+	if input.Inflation != 0 {
+		realFutureValue = futureValue.Mul(1 / math.Pow(1+input.Inflation/100, float64(input.Years))).RoundCents()
+	}
+
+	return SavingsResult{
+		Initial:         input.Initial,
+		MonthlyDeposit:  input.MonthlyDeposit,
+		FutureValue:     futureValue,
+		RealFutureValue: realFutureValue,
+		TotalDeposits:   totalDeposits,
+		InterestEarned:  interestEarned,
+		Years:           input.Years,
+		NumberOfMonths:  numberOfMonths,
+	}, nil
+}
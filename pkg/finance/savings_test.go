@@ -1,4 +1,4 @@
-package internal
+package finance
 
 import (
 	"testing"
@@ -13,19 +13,19 @@ func TestCalculateSavings(t *testing.T) {
 		{
 			name: "Basic savings calculation",
 			input: SavingsInput{
-				Initial:        1000,
-				MonthlyDeposit: 100,
+				Initial:        NewMoney(1000),
+				MonthlyDeposit: NewMoney(100),
 				AnnualYield:    5,
 				Inflation:      2,
 				Years:          10,
 			},
 			expected: SavingsResult{
-				Initial:         1000,
-				MonthlyDeposit:  100,
-				FutureValue:     17175.24, // Calculated with compound interest formula
-				RealFutureValue: 14093.64, // FutureValue adjusted for inflation
-				TotalDeposits:   13000,    // 1000 + (100 * 12 * 10)
-				InterestEarned:  4175.24,  // FutureValue - TotalDeposits
+				Initial:         NewMoney(1000),
+				MonthlyDeposit:  NewMoney(100),
+				FutureValue:     NewMoney(17175.24), // Calculated with compound interest formula
+				RealFutureValue: NewMoney(14093.64), // FutureValue adjusted for inflation
+				TotalDeposits:   NewMoney(13000),    // 1000 + (100 * 12 * 10)
+				InterestEarned:  NewMoney(4175.24),  // FutureValue - TotalDeposits
 				Years:           10,
 				NumberOfMonths:  120,
 			},
@@ -33,19 +33,19 @@ func TestCalculateSavings(t *testing.T) {
 		{
 			name: "Zero yield savings",
 			input: SavingsInput{
-				Initial:        5000,
-				MonthlyDeposit: 200,
+				Initial:        NewMoney(5000),
+				MonthlyDeposit: NewMoney(200),
 				AnnualYield:    0,
 				Inflation:      2,
 				Years:          5,
 			},
 			expected: SavingsResult{
-				Initial:         5000,
-				MonthlyDeposit:  200,
-				FutureValue:     17000,    // 5000 + (200 * 12 * 5)
-				RealFutureValue: 15401.33, // FutureValue adjusted for inflation
-				TotalDeposits:   17000,    // Same as future value with zero yield
-				InterestEarned:  0,        // No interest with zero yield
+				Initial:         NewMoney(5000),
+				MonthlyDeposit:  NewMoney(200),
+				FutureValue:     NewMoney(17000),    // 5000 + (200 * 12 * 5)
+				RealFutureValue: NewMoney(15401.33), // FutureValue adjusted for inflation
+				TotalDeposits:   NewMoney(17000),    // Same as future value with zero yield
+				InterestEarned:  NewMoney(0),        // No interest with zero yield
 				Years:           5,
 				NumberOfMonths:  60,
 			},
@@ -53,28 +53,51 @@ func TestCalculateSavings(t *testing.T) {
 		{
 			name: "High yield, long term savings",
 			input: SavingsInput{
-				Initial:        2000,
-				MonthlyDeposit: 500,
+				Initial:        NewMoney(2000),
+				MonthlyDeposit: NewMoney(500),
 				AnnualYield:    8,
 				Inflation:      2.5,
 				Years:          20,
 			},
 			expected: SavingsResult{
-				Initial:         2000,
-				MonthlyDeposit:  500,
-				FutureValue:     304363.81, // Calculated with compound interest formula
-				RealFutureValue: 186147.17, // FutureValue adjusted for inflation
-				TotalDeposits:   122000,    // 2000 + (500 * 12 * 20)
-				InterestEarned:  182363.81, // FutureValue - TotalDeposits
+				Initial:         NewMoney(2000),
+				MonthlyDeposit:  NewMoney(500),
+				FutureValue:     NewMoney(304363.81), // Calculated with compound interest formula
+				RealFutureValue: NewMoney(186147.17), // FutureValue adjusted for inflation
+				TotalDeposits:   NewMoney(122000),    // 2000 + (500 * 12 * 20)
+				InterestEarned:  NewMoney(182363.81), // FutureValue - TotalDeposits
 				Years:           20,
 				NumberOfMonths:  240,
 			},
 		},
+		{
+			name: "Negative yield and deflation",
+			input: SavingsInput{
+				Initial:        NewMoney(1000),
+				MonthlyDeposit: NewMoney(100),
+				AnnualYield:    -12,
+				Inflation:      -2,
+				Years:          1,
+			},
+			expected: SavingsResult{
+				Initial:         NewMoney(1000),
+				MonthlyDeposit:  NewMoney(100),
+				FutureValue:     NewMoney(2022.54), // Deposits shrink by 1% a month
+				RealFutureValue: NewMoney(2063.82), // Worth more after 2% deflation
+				TotalDeposits:   NewMoney(2200),
+				InterestEarned:  NewMoney(-177.46),
+				Years:           1,
+				NumberOfMonths:  12,
+			},
+		},
 	}
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			result := CalculateSavings(tc.input)
+			result, err := CalculateSavings(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
 
 			// Check basic fields
 			if result.Initial != tc.expected.Initial {
@@ -93,16 +116,16 @@ func TestCalculateSavings(t *testing.T) {
 			// For floating point values, use approximate comparison
 			const tolerance = 0.01 // 1% tolerance
 
-			if !approximatelyEqual(result.FutureValue, tc.expected.FutureValue, tolerance) {
+			if !approximatelyEqual(result.FutureValue.Float64(), tc.expected.FutureValue.Float64(), tolerance) {
 				t.Errorf("FutureValue = %v, want approximately %v", result.FutureValue, tc.expected.FutureValue)
 			}
-			if !approximatelyEqual(result.RealFutureValue, tc.expected.RealFutureValue, tolerance) {
+			if !approximatelyEqual(result.RealFutureValue.Float64(), tc.expected.RealFutureValue.Float64(), tolerance) {
 				t.Errorf("RealFutureValue = %v, want approximately %v", result.RealFutureValue, tc.expected.RealFutureValue)
 			}
-			if !approximatelyEqual(result.TotalDeposits, tc.expected.TotalDeposits, tolerance) {
+			if !approximatelyEqual(result.TotalDeposits.Float64(), tc.expected.TotalDeposits.Float64(), tolerance) {
 				t.Errorf("TotalDeposits = %v, want approximately %v", result.TotalDeposits, tc.expected.TotalDeposits)
 			}
-			if !approximatelyEqual(result.InterestEarned, tc.expected.InterestEarned, tolerance) {
+			if !approximatelyEqual(result.InterestEarned.Float64(), tc.expected.InterestEarned.Float64(), tolerance) {
 				t.Errorf("InterestEarned = %v, want approximately %v", result.InterestEarned, tc.expected.InterestEarned)
 			}
 		})
@@ -114,24 +137,27 @@ func TestSavingsEdgeCases(t *testing.T) {
 	// Test case with zero initial amount
 	t.Run("Zero initial amount", func(t *testing.T) {
 		input := SavingsInput{
-			Initial:        0,
-			MonthlyDeposit: 100,
+			Initial:        NewMoney(0),
+			MonthlyDeposit: NewMoney(100),
 			AnnualYield:    6,
 			Inflation:      2,
 			Years:          5,
 		}
 
-		result := CalculateSavings(input)
+		result, err := CalculateSavings(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
 
 		// Future value should only reflect the monthly deposits plus interest
-		if result.FutureValue <= result.TotalDeposits && input.AnnualYield > 0 {
+		if result.FutureValue.Cmp(result.TotalDeposits) <= 0 && input.AnnualYield > 0 {
 			t.Errorf("With positive yield, FutureValue (%v) should be greater than TotalDeposits (%v)",
 				result.FutureValue, result.TotalDeposits)
 		}
 
 		// Total deposits should be monthly deposit * number of months
-		expectedTotalDeposits := input.MonthlyDeposit * float64(result.NumberOfMonths)
-		if !approximatelyEqual(result.TotalDeposits, expectedTotalDeposits, 0.001) {
+		expectedTotalDeposits := input.MonthlyDeposit.Mul(float64(result.NumberOfMonths))
+		if result.TotalDeposits != expectedTotalDeposits {
 			t.Errorf("TotalDeposits = %v, want %v", result.TotalDeposits, expectedTotalDeposits)
 		}
 	})
@@ -139,18 +165,21 @@ func TestSavingsEdgeCases(t *testing.T) {
 	// Test case with zero monthly deposit
 	t.Run("Zero monthly deposit", func(t *testing.T) {
 		input := SavingsInput{
-			Initial:        10000,
-			MonthlyDeposit: 0,
+			Initial:        NewMoney(10000),
+			MonthlyDeposit: NewMoney(0),
 			AnnualYield:    4,
 			Inflation:      2,
 			Years:          10,
 		}
 
-		result := CalculateSavings(input)
+		result, err := CalculateSavings(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
 
 		// Future value should only reflect the initial amount plus interest
 		// This is a rough approximation, so we use a larger tolerance
-		if result.FutureValue < input.Initial && input.AnnualYield > 0 {
+		if result.FutureValue.Cmp(input.Initial) < 0 && input.AnnualYield > 0 {
 			t.Errorf("With positive yield, FutureValue (%v) should be greater than Initial (%v)",
 				result.FutureValue, input.Initial)
 		}
@@ -164,14 +193,17 @@ func TestSavingsEdgeCases(t *testing.T) {
 	// Test case with zero years
 	t.Run("Zero years", func(t *testing.T) {
 		input := SavingsInput{
-			Initial:        5000,
-			MonthlyDeposit: 200,
+			Initial:        NewMoney(5000),
+			MonthlyDeposit: NewMoney(200),
 			AnnualYield:    7,
 			Inflation:      2,
 			Years:          0,
 		}
 
-		result := CalculateSavings(input)
+		result, err := CalculateSavings(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
 
 		// With zero years, future value should equal initial amount
 		if result.FutureValue != input.Initial {
@@ -184,7 +216,7 @@ func TestSavingsEdgeCases(t *testing.T) {
 		}
 
 		// Interest earned should be zero
-		if result.InterestEarned != 0 {
+		if !result.InterestEarned.IsZero() {
 			t.Errorf("InterestEarned = %v, want 0", result.InterestEarned)
 		}
 
@@ -197,24 +229,42 @@ func TestSavingsEdgeCases(t *testing.T) {
 	// Test case with negative yield (market downturn)
 	t.Run("Negative yield", func(t *testing.T) {
 		input := SavingsInput{
-			Initial:        20000,
-			MonthlyDeposit: 300,
+			Initial:        NewMoney(20000),
+			MonthlyDeposit: NewMoney(300),
 			AnnualYield:    -3,
 			Inflation:      2,
 			Years:          2,
 		}
 
-		result := CalculateSavings(input)
+		result, err := CalculateSavings(input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
 
 		// With negative yield, future value should be less than total deposits
-		if result.FutureValue >= result.TotalDeposits {
+		if result.FutureValue.Cmp(result.TotalDeposits) >= 0 {
 			t.Errorf("With negative yield, FutureValue (%v) should be less than TotalDeposits (%v)",
 				result.FutureValue, result.TotalDeposits)
 		}
 
 		// Interest earned should be negative
-		if result.InterestEarned >= 0 {
+		if result.InterestEarned.Cmp(NewMoney(0)) >= 0 {
 			t.Errorf("With negative yield, InterestEarned (%v) should be negative", result.InterestEarned)
 		}
 	})
+
+	// Test cases with invalid parameters
+	invalidInputs := map[string]SavingsInput{
+		"Negative initial":  {Initial: NewMoney(-1000), MonthlyDeposit: NewMoney(100), Years: 5},
+		"Negative deposit":  {Initial: NewMoney(1000), MonthlyDeposit: NewMoney(-100), Years: 5},
+		"Negative years":    {Initial: NewMoney(1000), MonthlyDeposit: NewMoney(100), Years: -5},
+		"Invalid inflation": {Initial: NewMoney(1000), MonthlyDeposit: NewMoney(100), Years: 5, Inflation: -100},
+	}
+	for name, input := range invalidInputs {
+		t.Run(name, func(t *testing.T) {
+			if _, err := CalculateSavings(input); err == nil {
+				t.Error("Expected a validation error, got nil")
+			}
+		})
+	}
 }
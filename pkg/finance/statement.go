@@ -0,0 +1,168 @@
+package finance
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultStatementCurrency is the currency of transactions whose statement does not state one
+const defaultStatementCurrency = "EUR"
+
+// Transaction is a line of a bank statement
+type Transaction struct {
+	Date     time.Time `json:"date"`
+	Amount   Money     `json:"amount"` // Negative for spending, positive for money received
+	Currency string    `json:"currency"`
+	Payee    string    `json:"payee"`
+	Memo     string    `json:"memo"`
+}
+
+// StatementInput describes how to read a bank statement. The column options only
+// apply to CSV files.
+type StatementInput struct {
+	DateColumn     string // Header of the booking date column (default: date)
+	PayeeColumn    string // Header of the payee column (default: payee)
+	MemoColumn     string // Header of the optional memo column (default: memo or description)
+	AmountColumn   string // Header of the amount column (default: amount)
+	CurrencyColumn string // Header of the optional currency column (default: currency)
+	DateFormat     string // Go time layout of the dates (default: 2006-01-02 for CSV, month first for QIF)
+	DecimalComma   bool   // Amounts are written as 1.234,56
+	Separator      rune   // Field separator, such as ';' (default: ',')
+	Currency       string // Currency of statements that do not state one (default: EUR)
+}
+
+// LoadStatement reads the transactions of a bank statement file. The format follows
+// the extension: .csv, .ofx or .qfx, .qif, or .xml for ISO 20022 CAMT.053.
+func LoadStatement(path string, input StatementInput) ([]Transaction, error) {
+	file, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ParseStatementCSV(file, input)
+	case ".ofx", ".qfx":
+		return ParseOFX(file)
+	case ".qif":
+		return ParseQIF(file, input)
+	case ".xml":
+		return ParseCAMT053(file)
+	default:
+		return nil, fmt.Errorf("unknown statement format %q (expected .csv, .ofx, .qfx, .qif or .xml)", filepath.Ext(path))
+	}
+}
+
+// ParseStatementCSV reads the transactions of a bank statement CSV file with a header row
+func ParseStatementCSV(r io.Reader, input StatementInput) ([]Transaction, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if input.Separator != 0 {
+		reader.Comma = input.Separator
+	}
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("CSV file is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dateColumn := columnIndex(header, input.DateColumn, "date")
+	payeeColumn := columnIndex(header, input.PayeeColumn, "payee")
+	memoColumn := columnIndex(header, input.MemoColumn, "memo")
+	if input.MemoColumn == "" && memoColumn < 0 {
+		memoColumn = columnIndex(header, "description", "")
+	}
+	amountColumn := columnIndex(header, input.AmountColumn, "amount")
+	currencyColumn := columnIndex(header, input.CurrencyColumn, "currency")
+	if dateColumn < 0 || payeeColumn < 0 || amountColumn < 0 {
+		return nil, fmt.Errorf("CSV header needs date, payee and amount columns, got %s", strings.Join(header, ","))
+	}
+
+	layout := input.DateFormat
+	if layout == "" {
+		layout = dateLayout
+	}
+
+	transactions := []Transaction{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if isBlankRecord(record) {
+			continue
+		}
+		if dateColumn >= len(record) || payeeColumn >= len(record) || amountColumn >= len(record) {
+			return nil, fmt.Errorf("line %d: missing date, payee or amount", line)
+		}
+
+		date, err := time.Parse(layout, strings.TrimSpace(record[dateColumn]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid date %q, expected %s", line, record[dateColumn], layout)
+		}
+		amount, err := parseStatementAmount(record[amountColumn], input.DecimalComma)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		transaction := Transaction{
+			Date:     date,
+			Amount:   amount,
+			Currency: statementCurrency("", input),
+			Payee:    strings.TrimSpace(record[payeeColumn]),
+		}
+		if memoColumn >= 0 && memoColumn < len(record) {
+			transaction.Memo = strings.TrimSpace(record[memoColumn])
+		}
+		if currencyColumn >= 0 && currencyColumn < len(record) {
+			transaction.Currency = statementCurrency(record[currencyColumn], input)
+		}
+		transactions = append(transactions, transaction)
+	}
+
+	return sortTransactions(transactions), nil
+}
+
+// parseStatementAmount reads an amount such as -12.50 or, with a decimal comma, -1.234,56.
+// A euro sign and spaces are ignored.
+func parseStatementAmount(value string, decimalComma bool) (Money, error) {
+	value = strings.NewReplacer("€", "", " ", "").Replace(value)
+	if decimalComma {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+	return ParseMoney(value)
+}
+
+// statementCurrency returns the currency code, or the default currency of the input when empty
+func statementCurrency(code string, input StatementInput) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		code = strings.ToUpper(strings.TrimSpace(input.Currency))
+	}
+	if code == "" {
+		code = defaultStatementCurrency
+	}
+	return code
+}
+
+// sortTransactions sorts transactions by date, keeping the statement order within a day
+func sortTransactions(transactions []Transaction) []Transaction {
+	sort.SliceStable(transactions, func(i, j int) bool { return transactions[i].Date.Before(transactions[j].Date) })
+	return transactions
+}
@@ -0,0 +1,151 @@
+package finance
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// camtDocument is the part of an ISO 20022 camt.053 bank-to-customer statement that
+// is imported. Element names match any namespace, so every message version is read.
+type camtDocument struct {
+	XMLName    xml.Name        `xml:"Document"`
+	Statements []camtStatement `xml:"BkToCstmrStmt>Stmt"`
+}
+
+type camtStatement struct {
+	Currency string      `xml:"Acct>Ccy"`
+	Entries  []camtEntry `xml:"Ntry"`
+}
+
+type camtEntry struct {
+	Amount      camtAmount    `xml:"Amt"`
+	Indicator   string        `xml:"CdtDbtInd"` // CRDT or DBIT
+	Status      camtStatus    `xml:"Sts"`
+	BookingDate camtDate      `xml:"BookgDt"`
+	ValueDate   camtDate      `xml:"ValDt"`
+	Info        string        `xml:"AddtlNtryInf"`
+	Details     []camtDetails `xml:"NtryDtls>TxDtls"`
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+// camtStatus is the text of Sts up to version 07, and its Cd element afterwards
+type camtStatus struct {
+	Text string `xml:",chardata"`
+	Code string `xml:"Cd"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+// camtDetails holds the related parties and the remittance information. The party
+// name is a child of Pty from version 08.
+type camtDetails struct {
+	Creditor      string   `xml:"RltdPties>Cdtr>Nm"`
+	CreditorParty string   `xml:"RltdPties>Cdtr>Pty>Nm"`
+	Debtor        string   `xml:"RltdPties>Dbtr>Nm"`
+	DebtorParty   string   `xml:"RltdPties>Dbtr>Pty>Nm"`
+	Remittance    []string `xml:"RmtInf>Ustrd"`
+}
+
+// ParseCAMT053 reads the booked entries of an ISO 20022 camt.053 statement. Debits
+// become negative amounts paid to the creditor, credits positive amounts received
+// from the debtor. Pending entries are skipped.
+func ParseCAMT053(r io.Reader) ([]Transaction, error) {
+	var document camtDocument
+	if err := xml.NewDecoder(r).Decode(&document); err != nil {
+		return nil, fmt.Errorf("reading CAMT.053 statement: %w", err)
+	}
+	if len(document.Statements) == 0 {
+		return nil, fmt.Errorf("not a CAMT.053 statement: no BkToCstmrStmt>Stmt element")
+	}
+
+	transactions := []Transaction{}
+	for _, statement := range document.Statements {
+		for i, entry := range statement.Entries {
+			status := strings.TrimSpace(entry.Status.Code + entry.Status.Text)
+			if status != "" && status != "BOOK" {
+				continue
+			}
+
+			transaction, err := entry.transaction(statement.Currency)
+			if err != nil {
+				return nil, fmt.Errorf("entry %d: %w", i+1, err)
+			}
+			transactions = append(transactions, transaction)
+		}
+	}
+
+	return sortTransactions(transactions), nil
+}
+
+// transaction converts an entry, using the account currency when the amount has none
+func (e camtEntry) transaction(currency string) (Transaction, error) {
+	date, err := e.BookingDate.parse()
+	if err != nil {
+		if date, err = e.ValueDate.parse(); err != nil {
+			return Transaction{}, fmt.Errorf("entry has no booking or value date")
+		}
+	}
+
+	amount, err := ParseMoney(e.Amount.Value)
+	if err != nil {
+		return Transaction{}, err
+	}
+	switch strings.TrimSpace(e.Indicator) {
+	case "DBIT":
+		amount = amount.Neg()
+	case "CRDT":
+	default:
+		return Transaction{}, fmt.Errorf("invalid CdtDbtInd %q, expected CRDT or DBIT", e.Indicator)
+	}
+
+	if e.Amount.Currency != "" {
+		currency = e.Amount.Currency
+	}
+	transaction := Transaction{
+		Date:     date,
+		Amount:   amount,
+		Currency: statementCurrency(currency, StatementInput{}),
+		Memo:     strings.TrimSpace(e.Info),
+	}
+
+	if len(e.Details) > 0 {
+		details := e.Details[0]
+		if amount.IsNegative() {
+			transaction.Payee = firstNonEmpty(details.Creditor, details.CreditorParty)
+		} else {
+			transaction.Payee = firstNonEmpty(details.Debtor, details.DebtorParty)
+		}
+		if remittance := strings.TrimSpace(strings.Join(details.Remittance, " ")); remittance != "" {
+			transaction.Memo = remittance
+		}
+	}
+	return transaction, nil
+}
+
+// parse reads the date, or the date part of the date and time
+func (d camtDate) parse() (time.Time, error) {
+	value := strings.TrimSpace(d.Date)
+	if value == "" && len(strings.TrimSpace(d.DateTime)) >= len(dateLayout) {
+		value = strings.TrimSpace(d.DateTime)[:len(dateLayout)]
+	}
+	return time.Parse(dateLayout, value)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
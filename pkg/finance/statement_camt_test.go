@@ -0,0 +1,50 @@
+package finance
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseCAMT053(t *testing.T) {
+	transactions, err := LoadStatement("testdata/camt053.xml", StatementInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The pending entry is skipped
+	checkTransactions(t, transactions, []Transaction{
+		{Date: day(time.September, 1), Amount: NewMoney(-1000), Currency: "EUR", Payee: "ACME Properties", Memo: "Rent September"},
+		{Date: day(time.September, 3), Amount: NewMoney(-84.20), Currency: "EUR", Memo: "POS COOP Milano"},
+		{Date: day(time.September, 15), Amount: NewMoney(3000), Currency: "EUR", Payee: "Employer SpA", Memo: "Salary September 2026"},
+	})
+}
+
+func TestParseCAMT053Version8(t *testing.T) {
+	transactions, err := LoadStatement("testdata/camt053_v08.xml", StatementInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	checkTransactions(t, transactions, []Transaction{
+		{Date: day(time.September, 10), Amount: NewMoney(-120.50), Currency: "CHF", Payee: "Migros"},
+	})
+}
+
+func TestParseCAMT053Errors(t *testing.T) {
+	invalid := map[string]string{
+		"Not XML":       "date,amount",
+		"Other message": "<Document><BkToCstmrDbtCdtNtfctn/></Document>",
+		"Invalid indicator": "<Document><BkToCstmrStmt><Stmt><Ntry><Amt>1.00</Amt><CdtDbtInd>X</CdtDbtInd>" +
+			"<BookgDt><Dt>2026-09-01</Dt></BookgDt></Ntry></Stmt></BkToCstmrStmt></Document>",
+		"Missing date": "<Document><BkToCstmrStmt><Stmt><Ntry><Amt>1.00</Amt><CdtDbtInd>DBIT</CdtDbtInd>" +
+			"</Ntry></Stmt></BkToCstmrStmt></Document>",
+	}
+	for name, document := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseCAMT053(strings.NewReader(document)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package finance
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"time"
+)
+
+// ofxTransaction collects the elements of an OFX STMTTRN aggregate
+type ofxTransaction struct {
+	posted   string
+	amount   string
+	currency string
+	name     string
+	memo     string
+}
+
+// ParseOFX reads the transactions of an OFX or QFX bank or credit card statement.
+// Both the SGML format of OFX 1.x, where leaf elements have no closing tags, and the
+// XML format of OFX 2.x are supported.
+func ParseOFX(r io.Reader) ([]Transaction, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Skip the header, which is not markup in OFX 1.x
+	body := string(data)
+	start := strings.Index(strings.ToUpper(body), "<OFX>")
+	if start < 0 {
+		return nil, fmt.Errorf("not an OFX file: no <OFX> element")
+	}
+	body = body[start:]
+
+	var (
+		transactions = []Transaction{}
+		current      *ofxTransaction
+		currency     string // CURDEF of the current statement
+		inCurrency   bool   // Inside the CURRENCY aggregate of a transaction
+	)
+	for {
+		open := strings.IndexByte(body, '<')
+		if open < 0 {
+			break
+		}
+		end := strings.IndexByte(body[open:], '>')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated OFX element")
+		}
+		tag := strings.ToUpper(strings.TrimSpace(body[open+1 : open+end]))
+		body = body[open+end+1:]
+
+		next := strings.IndexByte(body, '<')
+		if next < 0 {
+			next = len(body)
+		}
+		value := html.UnescapeString(strings.TrimSpace(body[:next]))
+
+		switch tag {
+		case "STMTTRN":
+			current = &ofxTransaction{}
+		case "/STMTTRN":
+			if current == nil {
+				return nil, fmt.Errorf("unexpected </STMTTRN>")
+			}
+			transaction, err := current.transaction(currency)
+			if err != nil {
+				return nil, fmt.Errorf("transaction %d: %w", len(transactions)+1, err)
+			}
+			transactions = append(transactions, transaction)
+			current = nil
+		case "CURRENCY":
+			inCurrency = true
+		case "/CURRENCY":
+			inCurrency = false
+		case "CURDEF":
+			currency = value
+		}
+
+		if current == nil {
+			continue
+		}
+		switch tag {
+		case "DTPOSTED":
+			current.posted = value
+		case "TRNAMT":
+			current.amount = value
+		case "NAME":
+			// NAME is either a leaf of STMTTRN or of its PAYEE aggregate
+			if current.name == "" {
+				current.name = value
+			}
+		case "MEMO":
+			current.memo = value
+		case "CURSYM":
+			if inCurrency {
+				current.currency = value
+			}
+		}
+	}
+
+	return sortTransactions(transactions), nil
+}
+
+// transaction converts the collected elements, using the statement currency by default
+func (t *ofxTransaction) transaction(currency string) (Transaction, error) {
+	// Dates are YYYYMMDD, optionally followed by a time and a time zone
+	if len(t.posted) < 8 {
+		return Transaction{}, fmt.Errorf("invalid DTPOSTED %q", t.posted)
+	}
+	date, err := time.Parse("20060102", t.posted[:8])
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid DTPOSTED %q", t.posted)
+	}
+
+	// Some banks write the amount with a decimal comma
+	amount, err := parseStatementAmount(t.amount, strings.Contains(t.amount, ",") && !strings.Contains(t.amount, "."))
+	if err != nil {
+		return Transaction{}, fmt.Errorf("invalid TRNAMT: %w", err)
+	}
+
+	if t.currency != "" {
+		currency = t.currency
+	}
+	return Transaction{
+		Date:     date,
+		Amount:   amount,
+		Currency: statementCurrency(currency, StatementInput{}),
+		Payee:    t.name,
+		Memo:     t.memo,
+	}, nil
+}
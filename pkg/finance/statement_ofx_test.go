@@ -0,0 +1,48 @@
+package finance
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOFX(t *testing.T) {
+	transactions, err := LoadStatement("testdata/statement.ofx", StatementInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	checkTransactions(t, transactions, []Transaction{
+		{Date: day(time.September, 1), Amount: NewMoney(-1000), Currency: "EUR", Payee: "ACME Properties & Co", Memo: "Rent September"},
+		{Date: day(time.September, 3), Amount: NewMoney(-84.20), Currency: "EUR", Payee: "COOP Milano", Memo: "Groceries"},
+		{Date: day(time.September, 15), Amount: NewMoney(3000), Currency: "EUR", Payee: "Employer SpA", Memo: "Salary"},
+		{Date: day(time.September, 20), Amount: NewMoney(-12.99), Currency: "USD", Payee: "Streaming Inc"},
+	})
+}
+
+func TestParseOFXVersion2(t *testing.T) {
+	transactions, err := LoadStatement("testdata/statement_v2.ofx", StatementInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	checkTransactions(t, transactions, []Transaction{
+		{Date: day(time.September, 12), Amount: NewMoney(-45.50), Currency: "GBP", Payee: "Train Tickets Ltd", Memo: "London <> Leeds"},
+	})
+}
+
+func TestParseOFXErrors(t *testing.T) {
+	invalid := map[string]string{
+		"No OFX element": "OFXHEADER:100\n<HTML></HTML>",
+		"Missing date":   "<OFX><STMTTRN><TRNAMT>-1.00</STMTTRN></OFX>",
+		"Invalid amount": "<OFX><STMTTRN><DTPOSTED>20260901<TRNAMT>abc</STMTTRN></OFX>",
+		"Unterminated":   "<OFX><STMTTRN",
+	}
+	for name, document := range invalid {
+		t.Run(name, func(t *testing.T) {
+			if _, err := ParseOFX(strings.NewReader(document)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package finance
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// qifDateLayouts are tried in order when no date format is given. QIF files from US
+// software write the month first, and years after an apostrophe.
+var qifDateLayouts = []string{"1/2/2006", "1/2/06", "2006-01-02"}
+
+// qifTransactionTypes are the sections whose records are transactions
+var qifTransactionTypes = map[string]bool{"bank": true, "cash": true, "ccard": true, "oth a": true, "oth l": true}
+
+// ParseQIF reads the transactions of the bank, cash and credit card sections of a QIF
+// file. QIF has no currency, so the transactions are in the currency of the input.
+// Split lines are not imported: a split transaction counts with its total.
+func ParseQIF(r io.Reader, input StatementInput) ([]Transaction, error) {
+	scanner := bufio.NewScanner(r)
+
+	transactions := []Transaction{}
+	section := ""
+	record := map[byte]string{}
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimRight(strings.TrimPrefix(scanner.Text(), "\ufeff"), "\r ")
+		if text == "" {
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "!Type:"):
+			section = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(text, "!Type:")))
+		case strings.HasPrefix(text, "!Option:"), strings.HasPrefix(text, "!Clear:"):
+			// Switches of the account list, not a new section
+		case strings.HasPrefix(text, "!"):
+			section = strings.ToLower(text)
+		case text == "^":
+			if qifTransactionTypes[section] && len(record) > 0 {
+				transaction, err := qifTransaction(record, input)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %w", line, err)
+				}
+				transactions = append(transactions, transaction)
+			}
+			record = map[byte]string{}
+		default:
+			// The first character is the field code; split fields (S, E, $) repeat and are skipped
+			if _, seen := record[text[0]]; !seen {
+				record[text[0]] = strings.TrimSpace(text[1:])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return sortTransactions(transactions), nil
+}
+
+// qifTransaction converts the fields of a QIF record
+func qifTransaction(record map[byte]string, input StatementInput) (Transaction, error) {
+	date, err := parseQIFDate(record['D'], input.DateFormat)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	value, ok := record['T']
+	if !ok {
+		value = record['U']
+	}
+	if !input.DecimalComma {
+		value = strings.ReplaceAll(value, ",", "") // Thousands separators
+	}
+	amount, err := parseStatementAmount(value, input.DecimalComma)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	return Transaction{
+		Date:     date,
+		Amount:   amount,
+		Currency: statementCurrency("", input),
+		Payee:    record['P'],
+		Memo:     record['M'],
+	}, nil
+}
+
+// parseQIFDate reads a date such as 9/3/2026, 9/ 3'26 or 2026-09-03
+func parseQIFDate(value, layout string) (time.Time, error) {
+	if layout != "" {
+		date, err := time.Parse(layout, strings.TrimSpace(value))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q, expected %s", value, layout)
+		}
+		return date, nil
+	}
+
+	normalized := strings.NewReplacer(" ", "", "'", "/").Replace(value)
+	for _, layout := range qifDateLayouts {
+		if date, err := time.Parse(layout, normalized); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", value)
+}
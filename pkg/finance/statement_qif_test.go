@@ -0,0 +1,38 @@
+package finance
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQIF(t *testing.T) {
+	transactions, err := LoadStatement("testdata/statement.qif", StatementInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// The account list and the category list are not transactions; the split counts with its total
+	checkTransactions(t, transactions, []Transaction{
+		{Date: day(time.September, 1), Amount: NewMoney(-1000), Currency: "EUR", Payee: "ACME Properties", Memo: "Rent September"},
+		{Date: day(time.September, 3), Amount: NewMoney(-84.20), Currency: "EUR", Payee: "COOP Milano", Memo: "Groceries"},
+		{Date: day(time.September, 15), Amount: NewMoney(3000), Currency: "EUR", Payee: "Employer SpA", Memo: "Salary"},
+		{Date: day(time.September, 20), Amount: NewMoney(-100), Currency: "EUR", Payee: "Supermarket"},
+	})
+}
+
+func TestParseQIFOptions(t *testing.T) {
+	file := "!Type:CCard\nD03.09.2026\nT-1.234,56\nPHotel\n^\n"
+
+	transactions, err := ParseQIF(strings.NewReader(file), StatementInput{DateFormat: "02.01.2006", DecimalComma: true, Currency: "chf"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkTransactions(t, transactions, []Transaction{
+		{Date: day(time.September, 3), Amount: NewMoney(-1234.56), Currency: "CHF", Payee: "Hotel"},
+	})
+
+	if _, err := ParseQIF(strings.NewReader(file), StatementInput{}); err == nil {
+		t.Error("Expected an error for a day-first date without a date format")
+	}
+}
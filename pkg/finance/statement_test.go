@@ -0,0 +1,84 @@
+package finance
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// day returns midnight UTC of a date in 2026
+func day(month time.Month, d int) time.Time {
+	return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+// checkTransactions compares imported transactions with the expected ones
+func checkTransactions(t *testing.T, got, want []Transaction) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("Transactions = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if !got[i].Date.Equal(want[i].Date) || got[i].Amount != want[i].Amount || got[i].Currency != want[i].Currency ||
+			got[i].Payee != want[i].Payee || got[i].Memo != want[i].Memo {
+			t.Errorf("Transactions[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLoadStatement(t *testing.T) {
+	transactions, err := LoadStatement("testdata/statement.csv", StatementInput{})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	checkTransactions(t, transactions, []Transaction{
+		{Date: day(time.September, 1), Amount: NewMoney(-1000), Currency: "EUR", Payee: "ACME Properties", Memo: "Rent September"},
+		{Date: day(time.September, 3), Amount: NewMoney(-84.20), Currency: "EUR", Payee: "COOP Milano", Memo: "Groceries"},
+		{Date: day(time.September, 20), Amount: NewMoney(-12.99), Currency: "USD", Payee: "Streaming Inc"},
+	})
+
+	// Every format reads the same transaction from its fixture
+	for _, path := range []string{"testdata/statement.ofx", "testdata/statement.qif", "testdata/camt053.xml"} {
+		transactions, err := LoadStatement(path, StatementInput{})
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", path, err)
+		}
+		if transactions[0].Amount != NewMoney(-1000) || !transactions[0].Date.Equal(day(time.September, 1)) {
+			t.Errorf("%s: first transaction = %+v, want the rent of September 1", path, transactions[0])
+		}
+	}
+
+	if _, err := LoadStatement("testdata/statement.pdf", StatementInput{}); err == nil {
+		t.Error("Expected an error for an unknown format")
+	}
+}
+
+func TestParseStatementCSV(t *testing.T) {
+	file := "Booking Date;Counterparty;Amount\n03/09/2026;COOP;-1.234,56\n01/09/2026;Employer;2.000,00\n"
+
+	if _, err := ParseStatementCSV(strings.NewReader(file), StatementInput{}); err == nil {
+		t.Error("Expected an error for missing columns")
+	}
+
+	transactions, err := ParseStatementCSV(strings.NewReader(file), StatementInput{
+		DateColumn:   "Booking Date",
+		PayeeColumn:  "Counterparty",
+		DateFormat:   "02/01/2006",
+		DecimalComma: true,
+		Separator:    ';',
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(transactions) != 2 {
+		t.Fatalf("Transactions = %+v, want 2", transactions)
+	}
+	if transactions[0].Payee != "Employer" || transactions[1].Amount != NewMoney(-1234.56) {
+		t.Errorf("Transactions = %+v, want them sorted by date with -1234.56 for COOP", transactions)
+	}
+
+	if _, err := ParseStatementCSV(strings.NewReader("date,payee,amount\n2026-09-01,COOP,abc\n"), StatementInput{}); err == nil {
+		t.Error("Expected an error for an invalid amount")
+	}
+}